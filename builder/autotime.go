@@ -0,0 +1,96 @@
+package builder
+
+import "github.com/rumis/seal/options"
+
+// AutoTimeFunc return the current time encoded as the value of the time columns
+type AutoTimeFunc func() (interface{}, error)
+
+// autoTime fills the created and updated time columns of the values
+type autoTime struct {
+	now AutoTimeFunc
+	ts  options.TableTimestamps
+}
+
+// columns return the created and updated time columns declared by the table option and the struct tags
+func (r valueRow) columns(ts options.TableTimestamps) (create []string, update []string) {
+	if ts.Create != "" {
+		create = append(create, ts.Create)
+	}
+	if ts.Update != "" {
+		update = append(update, ts.Update)
+	}
+	if r.schema == nil {
+		return
+	}
+	for _, f := range r.schema.Fields {
+		if f.AutoCreateTime && f.Column != ts.Create {
+			create = append(create, f.Column)
+		}
+		if f.AutoUpdateTime && f.Column != ts.Update {
+			update = append(update, f.Column)
+		}
+	}
+	return
+}
+
+// known return the time columns of the row, they are known by the column filter as they are filled later
+func (a *autoTime) known(row valueRow) []string {
+	if a == nil {
+		return nil
+	}
+	create, update := row.columns(a.ts)
+	return append(create, update...)
+}
+
+// fillInsert fills the time columns of the rows which are not set, the columns omitted by the filter are skipped
+func (a *autoTime) fillInsert(rows []valueRow, f columnFilter) error {
+	var now interface{}
+	for _, row := range rows {
+		create, update := row.columns(a.ts)
+		for _, col := range append(create, update...) {
+			if !row.isZero(col) || f.omitted(col) {
+				continue
+			}
+			if now == nil {
+				var err error
+				if now, err = a.now(); err != nil {
+					return err
+				}
+			}
+			row.vals[col] = now
+		}
+	}
+	return nil
+}
+
+// fillUpdate sets the updated time columns, and removes the created time columns which are not set in the struct.
+// The columns explicitly set in a map are kept, and the columns omitted by the filter are skipped.
+func (a *autoTime) fillUpdate(row valueRow, f columnFilter) error {
+	create, update := row.columns(a.ts)
+	fromStruct := row.src.IsValid()
+	for _, col := range create {
+		if fromStruct && row.isZero(col) {
+			delete(row.vals, col)
+		}
+	}
+	for _, col := range update {
+		if _, ok := row.vals[col]; (ok && !fromStruct) || f.omitted(col) {
+			continue
+		}
+		now, err := a.now()
+		if err != nil {
+			return err
+		}
+		row.vals[col] = now
+	}
+	return nil
+}
+
+// copyMap return a shallow copy of the map, so the map of the caller is not changed
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
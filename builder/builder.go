@@ -32,4 +32,10 @@ type Builder interface {
 	Limit(int64, int64) string
 	// Placeholder generates the placeholder char
 	Placeholder() string
+	// Dialect returns the name of the sql dialect, e.g. mysql, sqlite3 or standard for the other databases
+	Dialect() string
+	// Explain generates the statement which shows the execution plan of the sql
+	Explain(sql string) string
+	// Literal generates the sql literal of the value, it is only used for displaying the sql
+	Literal(val interface{}) string
 }
@@ -1,5 +1,7 @@
 package builder
 
+import "strings"
+
 type BuilderMysql struct {
 	BuilderStandard
 }
@@ -8,3 +10,18 @@ type BuilderMysql struct {
 func NewMysqlBuilder() Builder {
 	return BuilderMysql{}
 }
+
+// Dialect returns the name of the sql dialect
+func (q BuilderMysql) Dialect() string {
+	return "mysql"
+}
+
+// mysqlEscaper escapes the string as mysql does when NO_BACKSLASH_ESCAPES is disabled
+var mysqlEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// Literal generates the sql literal of the value, it is only used for displaying the sql
+func (q BuilderMysql) Literal(val interface{}) string {
+	return literal(val, func(s string) string {
+		return "'" + mysqlEscaper.Replace(s) + "'"
+	})
+}
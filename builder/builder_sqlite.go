@@ -8,3 +8,13 @@ type BuilderSqlite struct {
 func NewSqliteBuilder() Builder {
 	return BuilderSqlite{}
 }
+
+// Dialect returns the name of the sql dialect
+func (q BuilderSqlite) Dialect() string {
+	return "sqlite3"
+}
+
+// Explain generates the statement which shows the query plan of the sql
+func (q BuilderSqlite) Explain(sql string) string {
+	return "EXPLAIN QUERY PLAN " + sql
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/rumis/seal/expr"
@@ -159,8 +160,15 @@ func (q BuilderStandard) Delete(table string) string {
 
 // Update generate the UPDATE clause
 func (q BuilderStandard) Update(table string, values map[string]interface{}, params expr.Params) string {
+	// sort the columns to make the sql deterministic
+	cols := make([]string, 0, len(values))
+	for k := range values {
+		cols = append(cols, k)
+	}
+	sort.Strings(cols)
 	lines := make([]string, 0, len(values))
-	for k, v := range values {
+	for _, k := range cols {
+		v := values[k]
 		if e, ok := v.(expr.Expr); ok {
 			lines = append(lines, k+"="+e.Build(params))
 		} else {
@@ -194,3 +202,20 @@ func (q BuilderStandard) Insert(table string, cols []string, vals [][]interface{
 func (q BuilderStandard) Placeholder() string {
 	return "?"
 }
+
+// Dialect returns the name of the sql dialect
+func (q BuilderStandard) Dialect() string {
+	return "standard"
+}
+
+// Explain generates the statement which shows the execution plan of the sql
+func (q BuilderStandard) Explain(sql string) string {
+	return "EXPLAIN " + sql
+}
+
+// Literal generates the sql literal of the value, it is only used for displaying the sql
+func (q BuilderStandard) Literal(val interface{}) string {
+	return literal(val, func(s string) string {
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	})
+}
@@ -2,6 +2,7 @@ package builder
 
 import (
 	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
 	"github.com/rumis/seal/utils"
 )
 
@@ -14,6 +15,10 @@ type Delete struct {
 
 	table string
 	where expr.Expr
+	all   bool
+	sd    SoftDelete
+	// at fills the updated time columns of the UPDATE converted by ToUpdate
+	at *autoTime
 }
 
 // NewDelete
@@ -56,9 +61,48 @@ func (d *Delete) OrWhere(e expr.Expr) *Delete {
 	return d
 }
 
+// All allows the statement to delete all the rows when the WHERE clause is not set or always true
+func (d *Delete) All() *Delete {
+	d.all = true
+	return d
+}
+
+// SoftDelete excludes the soft deleted rows, or only deletes them if sd.Only is set
+func (d *Delete) SoftDelete(sd SoftDelete) *Delete {
+	d.sd = sd
+	return d
+}
+
+// AutoTime sets the updated time columns to now in the UPDATE converted by ToUpdate, see Update.AutoTime
+func (d *Delete) AutoTime(now AutoTimeFunc, ts options.TableTimestamps) *Delete {
+	d.at = &autoTime{now: now, ts: ts}
+	return d
+}
+
+// ToUpdate converts the statement into an UPDATE of the same rows which sets the columns to val,
+// it is used to soft delete the rows
+func (d *Delete) ToUpdate(val map[string]interface{}) *Update {
+	u := NewUpdate(d.b).Table(d.table)
+	u.at = d.at
+	u.Value(val).SoftDelete(d.sd)
+	u.where = d.where
+	u.all = d.all
+	return u
+}
+
+// TableName return the table of the statement
+func (d *Delete) TableName() string {
+	return d.table
+}
+
 // ToSql build the sql clauses and params
 func (d *Delete) ToSql() (string, []interface{}, error) {
 	params := expr.Params{}
-	sql := d.b.Delete(d.table) + " " + d.b.Where(d.where, params)
+	where := d.b.Where(d.where, params)
+	if err := checkWhere("delete", d.table, d.where != nil, where, d.all); err != nil {
+		return "", nil, err
+	}
+	where = scopeWhere(where, d.sd.cond(d.table, false, d.sd.Only))
+	sql := d.b.Delete(d.table) + " " + where
 	return utils.ReplacePlaceHolders(sql, d.b.Placeholder(), params)
 }
@@ -1,9 +1,11 @@
 package builder
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,3 +41,59 @@ func BenchmarkDeleteString(b *testing.B) {
 		}).ToSql()
 	}
 }
+
+func TestDeleteSafety(t *testing.T) {
+
+	b := &BuilderStandard{}
+
+	_, _, err := NewDelete(b).Table("student").ToSql()
+	assert.EqualError(t, err, "unsafe delete on student: no WHERE clause, call All() to delete all rows")
+	assert.True(t, errors.Is(err, ErrUnsafeStatement))
+
+	_, _, err = NewDelete(b).Table("student").Where(expr.NotIn("id")).ToSql()
+	var se *SafetyError
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, SafetyError{Op: "delete", Table: "student", Reason: "WHERE clause is always true, call All() to delete all rows"}, *se)
+
+	_, _, err = NewDelete(b).Table("student").Where(expr.New("(1 = 1)")).ToSql()
+	assert.True(t, errors.Is(err, ErrUnsafeStatement))
+
+	sql, _, err := NewDelete(b).Table("student").All().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE FROM student ", sql)
+}
+
+func TestDeleteSoftDelete(t *testing.T) {
+
+	b := &BuilderStandard{}
+	sd := SoftDelete{Columns: map[string]string{"student": "deleted_at"}}
+
+	d := NewDelete(b).Table("student").Where(expr.Op("id", "=", 1)).SoftDelete(sd)
+	sql, args, err := d.ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE FROM student WHERE (id=?) AND deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{1}, args)
+
+	sql, args, err = d.ToUpdate(map[string]interface{}{"deleted_at": "2021-10-01 08:00:00"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET deleted_at=? WHERE (id=?) AND deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{"2021-10-01 08:00:00", 1}, args)
+
+	// the updated time is set by the converted update
+	now := func() (interface{}, error) { return "2021-10-01 08:00:00", nil }
+	sql, args, err = NewDelete(b).Table("student").Where(expr.Op("id", "=", 1)).SoftDelete(sd).
+		AutoTime(now, options.TableTimestamps{Update: "update_time"}).
+		ToUpdate(map[string]interface{}{"deleted_at": "2021-10-01 08:00:00"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET deleted_at=?, update_time=? WHERE (id=?) AND deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{"2021-10-01 08:00:00", "2021-10-01 08:00:00", 1}, args)
+
+	// the scope is not a filter of the safety check
+	_, _, err = NewDelete(b).Table("student").SoftDelete(sd).ToUpdate(map[string]interface{}{"deleted_at": 1}).ToSql()
+	assert.True(t, errors.Is(err, ErrUnsafeStatement))
+
+	sd.Only = true
+	sql, _, err = NewDelete(b).Table("student").SoftDelete(sd).All().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "DELETE FROM student WHERE deleted_at IS NOT NULL", sql)
+}
@@ -0,0 +1,73 @@
+package builder
+
+import "fmt"
+
+// columnFilter selects the columns of the values by Only and Omit
+type columnFilter struct {
+	only []string
+	omit []string
+}
+
+// enabled reports whether Only or Omit is set
+func (f columnFilter) enabled() bool {
+	return len(f.only) > 0 || len(f.omit) > 0
+}
+
+// omitted reports whether the column is omitted
+func (f columnFilter) omitted(col string) bool {
+	for _, c := range f.omit {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+// apply selects the columns of the row. The field selected by only but dropped by omitempty is added back with its zero value.
+// The column which is neither in the row nor in the struct nor in known is unknown.
+func (f columnFilter) apply(row valueRow, known []string) error {
+	if !f.enabled() {
+		return nil
+	}
+	isKnown := func(col string) bool {
+		if _, ok := row.vals[col]; ok {
+			return true
+		}
+		if row.schema != nil && row.schema.Field(col) != nil {
+			return true
+		}
+		for _, k := range known {
+			if k == col {
+				return true
+			}
+		}
+		return false
+	}
+	for _, col := range append(f.only, f.omit...) {
+		if !isKnown(col) {
+			return fmt.Errorf("unknown column %s", col)
+		}
+	}
+	for _, col := range f.omit {
+		delete(row.vals, col)
+	}
+	if len(f.only) == 0 {
+		return nil
+	}
+	selected := make(map[string]bool, len(f.only))
+	for _, col := range f.only {
+		selected[col] = true
+		if _, ok := row.vals[col]; ok || f.omitted(col) || row.schema == nil || !row.src.IsValid() {
+			continue
+		}
+		if field := row.schema.Field(col); field != nil {
+			row.vals[col] = field.Interface(row.src)
+		}
+	}
+	for col := range row.vals {
+		if !selected[col] {
+			delete(row.vals, col)
+		}
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package builder
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/rumis/seal/expr"
 	"github.com/rumis/seal/options"
@@ -18,6 +20,13 @@ type Insert struct {
 	cols  []string
 	table string
 	vals  [][]interface{}
+
+	// at fills the created and updated time columns, it is disabled if nil
+	at     *autoTime
+	filter columnFilter
+
+	// err is the first error occurred when setting the values, it is returned by ToSql
+	err error
 }
 
 // NewInsert
@@ -35,11 +44,59 @@ func (i *Insert) Into(table string) *Insert {
 }
 
 // Columns specifies which columns to insert.
+// The values of the maps and structs are taken by the columns in order, the columns which are not in a value are inserted as NULL.
 func (i *Insert) Columns(columns ...string) *Insert {
 	i.cols = columns
 	return i
 }
 
+// AutoTime fills the created and updated time columns with now when the values are set,
+// the columns are declared by ts and the autocreatetime and autoupdatetime tags of the struct values.
+// It must be called before the values are set, the values of []interface{} are not filled.
+func (i *Insert) AutoTime(now AutoTimeFunc, ts options.TableTimestamps) *Insert {
+	i.at = &autoTime{now: now, ts: ts}
+	return i
+}
+
+// Only sets only the columns of the values, the zero fields of the struct values dropped by omitempty are set if they are selected.
+// It is an error if a column is not in the values. It must be called before the values are set.
+func (i *Insert) Only(cols ...string) *Insert {
+	if len(i.vals) > 0 {
+		i.setErr(errors.New("Insert.Only: must be called before the values are set"))
+	}
+	i.filter.only = append(i.filter.only, cols...)
+	return i
+}
+
+// Omit drops the columns from the values, the time columns filled by AutoTime are dropped too.
+// It is an error if a column is not in the values. It must be called before the values are set.
+func (i *Insert) Omit(cols ...string) *Insert {
+	if len(i.vals) > 0 {
+		i.setErr(errors.New("Insert.Omit: must be called before the values are set"))
+	}
+	i.filter.omit = append(i.filter.omit, cols...)
+	return i
+}
+
+// prepare applies the column filter and fills the time columns of the rows converted from src, src is nil if the rows are maps
+func (i *Insert) prepare(call string, rows []map[string]interface{}, src interface{}) {
+	if i.at == nil && !i.filter.enabled() {
+		return
+	}
+	vrs := newValueRows(rows, src)
+	for n, row := range vrs {
+		if err := i.filter.apply(row, i.at.known(row)); err != nil {
+			i.setErr(fmt.Errorf("%s: row %d: %w", call, n, err))
+		}
+	}
+	if i.at == nil {
+		return
+	}
+	if err := i.at.fillInsert(vrs, i.filter); err != nil {
+		i.setErr(fmt.Errorf("%s: fill the time columns: %w", call, err))
+	}
+}
+
 // Values specifies the insert values
 // type of vals can be []map[string]interface{}, []struct , [][]interface{}
 // if type of vals is [][]interface{}, cols must be set first and be matched
@@ -51,16 +108,16 @@ func (i *Insert) Values(vals interface{}) *Insert {
 		i.vals = make([][]interface{}, 0, len(val))
 		// set columns only when Columns func is not called
 		if len(i.cols) == 0 {
-			i.cols = make([]string, 0, len(val[0]))
-			for k := range val[0] {
-				i.cols = append(i.cols, k)
-			}
+			i.cols = sortedKeys(val[0])
 		}
-		for _, vm := range val {
+		for n, vm := range val {
 			rowVal := make([]interface{}, 0, len(vm))
 			for _, k := range i.cols {
 				if i.eh != nil {
-					encodeRes, _ := i.eh(reflect.TypeOf(vm[k]), vm[k])
+					encodeRes, err := i.eh(reflect.TypeOf(vm[k]), vm[k])
+					if err != nil {
+						i.setErr(fmt.Errorf("Insert.Values: encode column %s of row %d: %w", k, n, err))
+					}
 					rowVal = append(rowVal, encodeRes)
 				} else {
 					rowVal = append(rowVal, vm[k])
@@ -71,6 +128,14 @@ func (i *Insert) Values(vals interface{}) *Insert {
 	}
 	switch val := vals.(type) {
 	case []map[string]interface{}:
+		if i.at != nil || i.filter.enabled() {
+			rows := make([]map[string]interface{}, len(val))
+			for n, vm := range val {
+				rows[n] = copyMap(vm)
+			}
+			i.prepare("Insert.Values", rows, nil)
+			val = rows
+		}
 		valsMapFunc(val)
 		return i
 	case [][]interface{}:
@@ -79,8 +144,11 @@ func (i *Insert) Values(vals interface{}) *Insert {
 	}
 	v, err := utils.Struct2MapSlice(vals)
 	if err != nil {
+		i.setErr(fmt.Errorf("Insert.Values: unsupported value type %T: %w", vals, err))
 		return i
 	}
+	stripRelations(v, vals)
+	i.prepare("Insert.Values", v, vals)
 	valsMapFunc(v)
 	return i
 }
@@ -88,18 +156,25 @@ func (i *Insert) Values(vals interface{}) *Insert {
 // Value specifies the insert value
 // type of vals can be map[string]interface{} , struct , []interface{}
 // if type of vals is []interface{}, cols must be set first and be matched
+// if cols is set, the values of map and struct are taken by cols
 func (i *Insert) Value(val interface{}) *Insert {
 	valMapFunc := func(val map[string]interface{}) {
-		i.cols = make([]string, 0, len(val))
+		// set columns only when Columns func is not called
+		if len(i.cols) == 0 {
+			i.cols = sortedKeys(val)
+		}
 		rowVal := make([]interface{}, 0, len(val))
-		for k, v := range val {
-			rowVal = append(rowVal, v)
-			i.cols = append(i.cols, k)
+		for _, k := range i.cols {
+			rowVal = append(rowVal, val[k])
 		}
 		i.vals = append(i.vals, rowVal)
 	}
 	switch v := val.(type) {
 	case map[string]interface{}:
+		if i.at != nil || i.filter.enabled() {
+			v = copyMap(v)
+			i.prepare("Insert.Value", []map[string]interface{}{v}, nil)
+		}
 		valMapFunc(v)
 		return i
 	case []interface{}:
@@ -108,20 +183,53 @@ func (i *Insert) Value(val interface{}) *Insert {
 	}
 	vm, err := utils.Struct2Map(val)
 	if err != nil {
+		i.setErr(fmt.Errorf("Insert.Value: unsupported value type %T: %w", val, err))
 		return i
 	}
+	stripRelations([]map[string]interface{}{vm}, val)
+	i.prepare("Insert.Value", []map[string]interface{}{vm}, val)
 	valMapFunc(vm)
 	return i
 }
 
+// sortedKeys return the sorted keys of the map, the columns are sorted to make the sql deterministic
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setErr records the error if it is the first one
+func (i *Insert) setErr(err error) {
+	if i.err == nil {
+		i.err = err
+	}
+}
+
+// TableName return the table of the statement
+func (i *Insert) TableName() string {
+	return i.table
+}
+
 // ToSql build the sql clauses and params
 func (i *Insert) ToSql() (string, []interface{}, error) {
+	if i.err != nil {
+		return "", nil, i.err
+	}
 	if len(i.cols) == 0 {
 		return "", nil, errors.New("insert columns not set")
 	}
 	if len(i.vals) == 0 {
 		return "", nil, errors.New("insert value not set")
 	}
+	for n, row := range i.vals {
+		if len(row) != len(i.cols) {
+			return "", nil, fmt.Errorf("insert row %d has %d values, but %d columns are set", n, len(row), len(i.cols))
+		}
+	}
 	params := expr.Params{}
 	sql := i.b.Insert(i.table, i.cols, i.vals, params)
 	return utils.ReplacePlaceHolders(sql, i.b.Placeholder(), params)
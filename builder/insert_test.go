@@ -1,7 +1,10 @@
 package builder
 
 import (
+	"errors"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/rumis/seal/options"
 	"github.com/stretchr/testify/assert"
@@ -129,3 +132,157 @@ func TestInsertValue(t *testing.T) {
 	// t.Error(sql1, arg1)
 
 }
+
+func TestInsertError(t *testing.T) {
+
+	b := &BuilderStandard{}
+
+	_, _, err := NewInsert(b, nil).Into("student").Value(13).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Insert.Value: unsupported value type int")
+
+	_, _, err = NewInsert(b, nil).Into("student").Values([]int{13}).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Insert.Values: unsupported value type []int")
+
+	hookErr := errors.New("bad age")
+	eh := func(typ reflect.Type, data interface{}) (interface{}, error) {
+		if v, ok := data.(int); ok && v < 0 {
+			return data, hookErr
+		}
+		return data, nil
+	}
+	_, _, err = NewInsert(b, eh).Into("student").Columns("age").Values([]map[string]interface{}{{"age": 1}, {"age": -1}}).ToSql()
+	assert.True(t, errors.Is(err, hookErr))
+	assert.EqualError(t, err, "Insert.Values: encode column age of row 1: bad age")
+
+	_, _, err = NewInsert(b, nil).Into("student").Columns("name", "age").Values([][]interface{}{
+		{"murong", 13},
+		{"liu"},
+	}).ToSql()
+	assert.EqualError(t, err, "insert row 1 has 1 values, but 2 columns are set")
+
+	// the first error is kept
+	_, _, err = NewInsert(b, nil).Into("student").Value(13).Value("x").ToSql()
+	assert.Contains(t, err.Error(), "unsupported value type int")
+}
+
+func TestInsertAutoTime(t *testing.T) {
+
+	b := &BuilderStandard{}
+	now := func() (interface{}, error) { return "2021-10-01 08:00:00", nil }
+	created := time.Date(2021, 9, 1, 8, 0, 0, 0, time.UTC)
+
+	// struct tags
+	type student struct {
+		Name       string    `seal:"name"`
+		CreateTime time.Time `seal:"create_time,autocreatetime"`
+		UpdateTime time.Time `seal:"update_time,autoupdatetime"`
+	}
+	sql, args, err := NewInsert(b, nil).Into("student").AutoTime(now, options.TableTimestamps{}).
+		Values([]student{{Name: "murong"}, {Name: "xiaoyao", CreateTime: created}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (create_time, name, update_time) VALUES (?,?,?), (?,?,?)", sql)
+	assert.Equal(t, []interface{}{"2021-10-01 08:00:00", "murong", "2021-10-01 08:00:00", "2021-09-01 08:00:00", "xiaoyao", "2021-10-01 08:00:00"}, args)
+
+	// table option, the map of the caller is not changed
+	val := map[string]interface{}{"name": "murong"}
+	sql, args, err = NewInsert(b, nil).Into("student").AutoTime(now, options.TableTimestamps{Create: "create_time"}).Value(val).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (create_time, name) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{"2021-10-01 08:00:00", "murong"}, args)
+	assert.Equal(t, map[string]interface{}{"name": "murong"}, val)
+
+	// clock error
+	_, _, err = NewInsert(b, nil).Into("student").AutoTime(func() (interface{}, error) { return nil, errors.New("clock") }, options.TableTimestamps{}).
+		Value(student{Name: "murong"}).ToSql()
+	assert.EqualError(t, err, "Insert.Value: fill the time columns: clock")
+}
+
+func TestInsertOnlyOmit(t *testing.T) {
+
+	b := &BuilderStandard{}
+	type student struct {
+		Name string `seal:"name"`
+		Age  int    `seal:"age,omitempty"`
+		Nick string `seal:"nick"`
+	}
+
+	// the zero field dropped by omitempty is forced
+	sql, args, err := NewInsert(b, nil).Into("student").Only("name", "age").
+		Values([]student{{Name: "murong", Nick: "m"}, {Name: "xiaoyao", Age: 14}}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (age, name) VALUES (?,?), (?,?)", sql)
+	assert.Equal(t, []interface{}{0, "murong", 14, "xiaoyao"}, args)
+
+	sql, args, err = NewInsert(b, nil).Into("student").Omit("nick").Value(&student{Name: "murong", Nick: "m"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (name) VALUES (?)", sql)
+	assert.Equal(t, []interface{}{"murong"}, args)
+
+	// map
+	val := map[string]interface{}{"name": "murong", "age": 13}
+	sql, args, err = NewInsert(b, nil).Into("student").Omit("age").Value(val).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (name) VALUES (?)", sql)
+	assert.Equal(t, []interface{}{"murong"}, args)
+	assert.Len(t, val, 2)
+
+	// unknown column
+	_, _, err = NewInsert(b, nil).Into("student").Only("name", "class").Value(student{Name: "murong"}).ToSql()
+	assert.EqualError(t, err, "Insert.Value: row 0: unknown column class")
+	_, _, err = NewInsert(b, nil).Into("student").Omit("class").Value(val).ToSql()
+	assert.EqualError(t, err, "Insert.Value: row 0: unknown column class")
+
+	_, _, err = NewInsert(b, nil).Into("student").Value(val).Only("name").ToSql()
+	assert.EqualError(t, err, "Insert.Only: must be called before the values are set")
+}
+
+func TestInsertColumnsValue(t *testing.T) {
+
+	b := &BuilderStandard{}
+	type student struct {
+		Name string `seal:"name"`
+		Age  int    `seal:"age"`
+		Nick string `seal:"nick"`
+	}
+
+	// the struct is inserted by the columns
+	sql, args, err := NewInsert(b, nil).Into("student").Columns("nick", "name").
+		Value(student{Name: "murong", Age: 13, Nick: "m"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (nick, name) VALUES (?,?)", sql)
+	assert.Equal(t, []interface{}{"m", "murong"}, args)
+
+	sql, args, err = NewInsert(b, nil).Into("student").Columns("age", "name").
+		Value(map[string]interface{}{"name": "murong", "age": 13, "nick": "m"}).
+		Value(&student{Name: "xiaoyao", Age: 14}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (age, name) VALUES (?,?), (?,?)", sql)
+	assert.Equal(t, []interface{}{13, "murong", 14, "xiaoyao"}, args)
+
+	// the columns of the first value are kept
+	sql, args, err = NewInsert(b, nil).Into("student").
+		Value(map[string]interface{}{"name": "murong", "age": 13}).
+		Value(map[string]interface{}{"age": 14, "name": "xiaoyao"}).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "INSERT INTO student (age, name) VALUES (?,?), (?,?)", sql)
+	assert.Equal(t, []interface{}{13, "murong", 14, "xiaoyao"}, args)
+}
+
+func TestValueRowNilSquash(t *testing.T) {
+	type Extra struct {
+		Age int `seal:"age,omitempty"`
+	}
+	type member struct {
+		*Extra `seal:",squash"`
+		Name   string `seal:"name"`
+	}
+	// the fields of a nil squashed pointer are zero
+	row := newValueRows([]map[string]interface{}{{"name": "murong"}}, member{Name: "murong"})[0]
+	assert.True(t, row.isZero("age"))
+	assert.False(t, row.isZero("name"))
+	f := columnFilter{only: []string{"name", "age"}}
+	assert.Nil(t, f.apply(row, nil))
+	assert.Equal(t, map[string]interface{}{"name": "murong", "age": nil}, row.vals)
+}
@@ -0,0 +1,86 @@
+package builder
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// literal generates the sql literal of the value, quote is used to quote the strings
+func literal(val interface{}, quote func(s string) string) string {
+	if valuer, ok := val.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return quote(fmt.Sprint(val))
+		}
+		val = v
+	}
+	switch v := val.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return quote(v)
+	case []byte:
+		return "X'" + hex.EncodeToString(v) + "'"
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case time.Time:
+		return quote(v.Format("2006-01-02 15:04:05"))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		return quote(fmt.Sprint(v))
+	}
+}
+
+// Interpolate replaces the placeholders of the sql with the literals of args.
+// The placeholders in quoted strings, quoted identifiers and comments are kept.
+// The result is only used for displaying the sql, never execute it.
+func Interpolate(b Builder, sql string, args []interface{}) string {
+	pl := b.Placeholder()
+	var s strings.Builder
+	n := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// copy the quoted part as is
+			end := i + 1
+			for end < len(sql) {
+				if sql[end] == '\\' && c == '\'' {
+					end += 2
+					continue
+				}
+				if sql[end] == c {
+					break
+				}
+				end++
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			s.WriteString(sql[i : end+1])
+			i = end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				s.WriteString(sql[i:])
+				return s.String()
+			}
+			s.WriteString(sql[i : i+2+end+2])
+			i += 2 + end + 1
+		case strings.HasPrefix(sql[i:], pl) && n < len(args):
+			s.WriteString(b.Literal(args[n]))
+			n++
+			i += len(pl) - 1
+		default:
+			s.WriteByte(c)
+		}
+	}
+	return s.String()
+}
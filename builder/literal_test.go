@@ -0,0 +1,24 @@
+package builder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolate(t *testing.T) {
+	ts := time.Date(2021, 10, 1, 8, 30, 0, 0, time.UTC)
+	args := []interface{}{"O'Neil", 3, nil, true, ts, []byte{0xab, 0x01}}
+	sql := "SELECT * FROM student WHERE name=? AND note='who?' AND age>? AND class IS ? AND active=? AND created=? AND raw=?"
+
+	assert.Equal(t,
+		"SELECT * FROM student WHERE name='O''Neil' AND note='who?' AND age>3 AND class IS NULL AND active=1 AND created='2021-10-01 08:30:00' AND raw=X'ab01'",
+		Interpolate(NewStandardBuilder(), sql, args))
+	assert.Equal(t,
+		`SELECT * FROM student WHERE name='O\'Neil' AND note='who?' AND age>3 AND class IS NULL AND active=1 AND created='2021-10-01 08:30:00' AND raw=X'ab01'`,
+		Interpolate(NewMysqlBuilder(), sql, args))
+
+	// missing args keep the placeholders
+	assert.Equal(t, "DELETE FROM student WHERE id=1 OR id=?", Interpolate(NewSqliteBuilder(), "DELETE FROM student WHERE id=? OR id=?", []interface{}{1}))
+}
@@ -0,0 +1,69 @@
+package builder
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/rumis/seal/schema"
+)
+
+// valueRow is a row of values with the struct it is converted from
+type valueRow struct {
+	vals map[string]interface{}
+	// src is the struct of the row, it is invalid if the row is a map
+	src    reflect.Value
+	schema *schema.Schema
+}
+
+// newValueRows pairs the rows with the elements of the structs src,
+// src is a struct or a slice of struct, or nil if the rows are maps
+func newValueRows(rows []map[string]interface{}, src interface{}) []valueRow {
+	vrs := make([]valueRow, len(rows))
+	var s *schema.Schema
+	var sv reflect.Value
+	if src != nil {
+		s, _ = schema.Parse(src)
+		sv = reflect.Indirect(reflect.ValueOf(src))
+	}
+	for n, row := range rows {
+		vrs[n].vals = row
+		if s == nil {
+			continue
+		}
+		vrs[n].schema = s
+		if sv.Kind() == reflect.Slice || sv.Kind() == reflect.Array {
+			vrs[n].src = reflect.Indirect(sv.Index(n))
+		} else {
+			vrs[n].src = sv
+		}
+	}
+	return vrs
+}
+
+// isZero reports whether the column of the row is not set
+func (r valueRow) isZero(col string) bool {
+	if r.schema != nil && r.src.IsValid() {
+		if f := r.schema.Field(col); f != nil {
+			return f.IsZero(r.src)
+		}
+	}
+	v, ok := r.vals[col]
+	if !ok || v == nil {
+		return true
+	}
+	if t, ok := v.(time.Time); ok {
+		return t.IsZero()
+	}
+	return false
+}
+
+// stripRelations removes the relation fields of the structs src from the rows converted from them
+func stripRelations(rows []map[string]interface{}, src interface{}) {
+	s, err := schema.Parse(src)
+	if err != nil || len(s.Relations) == 0 {
+		return
+	}
+	for _, row := range rows {
+		s.StripRelations(row)
+	}
+}
@@ -0,0 +1,78 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rumis/seal/expr"
+)
+
+// ErrUnsafeStatement is matched by errors.Is for all the SafetyError
+var ErrUnsafeStatement = errors.New("unsafe statement")
+
+// SafetyError reports a statement rejected by the safety guard,
+// e.g. a DELETE without WHERE or a SELECT without LIMIT when the max rows is set.
+type SafetyError struct {
+	// Op is the kind of the statement: delete, update or select
+	Op     string
+	Table  string
+	Reason string
+}
+
+// Error return the message of the error
+func (e *SafetyError) Error() string {
+	return fmt.Sprintf("unsafe %s on %s: %s", e.Op, e.Table, e.Reason)
+}
+
+// Is reports whether the target is ErrUnsafeStatement
+func (e *SafetyError) Is(target error) bool {
+	return target == ErrUnsafeStatement
+}
+
+// alwaysTrueRe matches the conditions which are always true, e.g. "1=1", "(TRUE)"
+var alwaysTrueRe = regexp.MustCompile(`(?i)^[\s(]*(1\s*=\s*1|TRUE|1)[\s)]*$`)
+
+// checkWhere reject the DELETE and UPDATE which affect all the rows unless all is set.
+// hasWhere reports whether the condition is set, where is the clause generated by Builder.Where
+func checkWhere(op string, table string, hasWhere bool, where string, all bool) error {
+	if all {
+		return nil
+	}
+	if !hasWhere {
+		return &SafetyError{Op: op, Table: table, Reason: "no WHERE clause, call All() to " + op + " all rows"}
+	}
+	cond := strings.TrimPrefix(where, "WHERE ")
+	if cond == "" || alwaysTrueRe.MatchString(cond) {
+		return &SafetyError{Op: op, Table: table, Reason: "WHERE clause is always true, call All() to " + op + " all rows"}
+	}
+	return nil
+}
+
+// checkLimit reject the SELECT which may return more than maxRows rows unless all is set.
+// The aggregate query without GROUP BY is allowed as it returns only one row.
+func (s *Select) checkLimit() error {
+	if s.maxRows <= 0 || s.all {
+		return nil
+	}
+	if s.limit > s.maxRows {
+		return &SafetyError{Op: "select", Table: s.TableName(), Reason: fmt.Sprintf("LIMIT %d exceeds the max rows %d", s.limit, s.maxRows)}
+	}
+	if s.limit > 0 {
+		return nil
+	}
+	if len(s.groupBy) == 0 && len(s.selects) > 0 {
+		agg := true
+		for _, col := range s.selects {
+			if _, ok := col.(expr.AggExp); !ok {
+				agg = false
+				break
+			}
+		}
+		if agg {
+			return nil
+		}
+	}
+	return &SafetyError{Op: "select", Table: s.TableName(), Reason: fmt.Sprintf("no LIMIT, at most %d rows are allowed, call All() to query all rows", s.maxRows)}
+}
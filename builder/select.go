@@ -1,6 +1,8 @@
 package builder
 
 import (
+	"strings"
+
 	"github.com/rumis/seal/expr"
 	"github.com/rumis/seal/utils"
 )
@@ -20,6 +22,10 @@ type Select struct {
 	having       expr.Expr
 	limit        int64
 	offset       int64
+	maxRows      int64
+	all          bool
+	aliasJoins   bool
+	sd           SoftDelete
 }
 
 // NewSelect
@@ -38,9 +44,18 @@ func (s *Select) Select(cols ...string) *Select {
 	return s
 }
 
-// AndSelect adds additional columns to be selected.
+// AndSelect adds additional columns of the table to be selected.
+// The plain columns are aliased with the table as prefix if AliasJoins is set and the query has joins, see AliasJoins.
+// Aliasing is deliberately not the default, so the result columns of the existing joins keep their plain names.
 func (s *Select) AndSelect(table string, cols ...string) *Select {
-	s.selects = append(s.selects, expr.ColumnExp{
+	if len(cols) == 0 {
+		s.selects = append(s.selects, expr.ColumnExp{
+			Columns: cols,
+			Table:   utils.AliasName(table),
+		})
+		return s
+	}
+	s.selects = append(s.selects, expr.PrefixColumnExp{
 		Columns: cols,
 		Table:   utils.AliasName(table),
 	})
@@ -211,28 +226,97 @@ func (s *Select) Offset(offset int64) *Select {
 	return s
 }
 
+// MaxRows rejects the statement which has no LIMIT or has a LIMIT greater than n, it is disabled when n is 0.
+// Only ToSql is guarded, the sub queries built by ToExpr are not.
+func (s *Select) MaxRows(n int64) *Select {
+	s.maxRows = n
+	return s
+}
+
+// All allows the statement to query all the rows regardless of the max rows
+func (s *Select) All() *Select {
+	s.all = true
+	return s
+}
+
+// AliasJoins aliases the plain columns added by AndSelect and the joins with the table as prefix when the query has joins,
+// e.g. AndSelect("class", "name") selects "class.name AS class__name", so the columns of the joined tables are not collapsed in the result rows.
+// The other columns are qualified with their tables instead of being stripped. By default, the columns are not aliased.
+func (s *Select) AliasJoins() *Select {
+	s.aliasJoins = true
+	return s
+}
+
+// JoinsAliased reports whether the plain columns of the joins are aliased with the table as prefix
+func (s *Select) JoinsAliased() bool {
+	return s.aliasJoins && len(s.join) > 0
+}
+
+// HasJoins reports whether the query joins tables
+func (s *Select) HasJoins() bool {
+	return len(s.join) > 0
+}
+
+// SoftDelete excludes the soft deleted rows of the tables, or only selects the deleted rows of the FROM tables if sd.Only is set.
+// The condition of the FROM tables is added to the WHERE clause, and the condition of the joined tables is added to the ON clauses.
+func (s *Select) SoftDelete(sd SoftDelete) *Select {
+	s.sd = sd
+	return s
+}
+
+// TableName return the first table of the FROM clause
+func (s *Select) TableName() string {
+	if len(s.from) == 0 {
+		return ""
+	}
+	return s.from[0]
+}
+
 // build build the sql and params
 func (s *Select) build() (string, expr.Params) {
 	params := expr.Params{}
 
-	if len(s.from) == 1 {
+	alias := s.JoinsAliased()
+	selects := make([]expr.Expr, len(s.selects))
+	for i, colExp := range s.selects {
+		selects[i] = colExp
+		// the prefixed columns are only aliased with joins
+		if col, ok := colExp.(expr.PrefixColumnExp); ok && !alias {
+			selects[i] = expr.ColumnExp{Columns: col.Columns, Table: col.Table}
+		}
+	}
+	if len(s.from) == 1 && !alias {
 		// if only one table, remove the table name from column
-		for i, colExp := range s.selects {
+		for i, colExp := range selects {
 			switch col := colExp.(type) {
 			case expr.ColumnExp:
 				col.Table = ""
-				s.selects[i] = col
+				selects[i] = col
 			case expr.AggExp:
 				col.Table = ""
-				s.selects[i] = col
+				selects[i] = col
 			}
 		}
 	}
+	joins := s.join
+	if len(s.sd.Columns) > 0 {
+		joins = s.sd.scopeJoins(joins, params)
+	}
+	join := s.b.Join(joins, params)
+	where := s.b.Where(s.where, params)
+	qualify := len(s.from) > 1 || len(s.join) > 0
+	var scopes []string
+	for _, table := range s.from {
+		if scope := s.sd.cond(table, qualify, s.sd.Only); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	where = scopeWhere(where, strings.Join(scopes, " AND "))
 	clauses := []string{
-		s.b.Select(s.selects, s.distinct, s.selectOption),
+		s.b.Select(selects, s.distinct, s.selectOption),
 		s.b.From(s.from),
-		s.b.Join(s.join, params),
-		s.b.Where(s.where, params),
+		join,
+		where,
 		s.b.GroupBy(s.groupBy),
 		s.b.Having(s.having, params),
 		s.b.OrderBy(s.orderBy),
@@ -253,6 +337,9 @@ func (s *Select) build() (string, expr.Params) {
 
 // ToSql
 func (s *Select) ToSql() (string, []interface{}, error) {
+	if err := s.checkLimit(); err != nil {
+		return "", nil, err
+	}
 	sql, params := s.build()
 	return utils.ReplacePlaceHolders(sql, s.b.Placeholder(), params)
 }
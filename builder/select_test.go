@@ -87,3 +87,96 @@ func TestAggregate(t *testing.T) {
 	assert.Equal(t, []interface{}{13, 14}, args)
 
 }
+
+func TestSelectMaxRows(t *testing.T) {
+
+	b := &BuilderStandard{}
+
+	_, _, err := NewSelect(b).From("student").MaxRows(100).ToSql()
+	assert.EqualError(t, err, "unsafe select on student: no LIMIT, at most 100 rows are allowed, call All() to query all rows")
+
+	_, _, err = NewSelect(b).From("student").MaxRows(100).Limit(101).ToSql()
+	assert.EqualError(t, err, "unsafe select on student: LIMIT 101 exceeds the max rows 100")
+
+	sql, _, err := NewSelect(b).From("student").MaxRows(100).Limit(100).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM student LIMIT 100", sql)
+
+	sql, _, err = NewSelect(b).From("student").MaxRows(100).All().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM student", sql)
+
+	// aggregate without group by returns only one row
+	_, _, err = NewSelect(b).Agg("COUNT", "id", "cnt").From("student").MaxRows(100).ToSql()
+	assert.Nil(t, err)
+	_, _, err = NewSelect(b).Agg("COUNT", "id", "cnt").From("student").GroupBy("age").MaxRows(100).ToSql()
+	assert.Error(t, err)
+
+	// sub query is not guarded
+	e := NewSelect(b).Select("id").From("student").MaxRows(100).ToExpr()
+	assert.Equal(t, "SELECT id FROM student", e.Build(expr.Params{}))
+}
+
+func TestSelectSoftDelete(t *testing.T) {
+
+	b := &BuilderStandard{}
+	sd := SoftDelete{Columns: map[string]string{"student": "deleted_at", "school": "removed_at"}}
+
+	sql, args, err := NewSelect(b).Select("name").From("student").
+		Where(expr.Op("age", "=", 13)).OrWhere(expr.Op("age", "=", 14)).
+		SoftDelete(sd).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT name FROM student WHERE (age=? OR age=?) AND deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{13, 14}, args)
+
+	// the joined tables are excluded in the ON clauses
+	sql, args, err = NewSelect(b).Select("name").From("student as c").
+		LeftJoin("school s", expr.Op("s.id", "=", 1), "id as school_id").
+		Where(expr.Op("c.age", "=", 13)).
+		SoftDelete(sd).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT name,id as school_id FROM student as c LEFT JOIN school s ON (s.id=?) AND s.removed_at IS NULL WHERE (c.age=?) AND c.deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{1, 13}, args)
+
+	sd.Only = true
+	sql, _, err = NewSelect(b).Select("name").From("student").SoftDelete(sd).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT name FROM student WHERE deleted_at IS NOT NULL", sql)
+
+	sql, _, err = NewSelect(b).Select("name").From("class").SoftDelete(sd).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT name FROM class", sql)
+}
+
+func TestSelectPrefixColumns(t *testing.T) {
+	b := &BuilderStandard{}
+
+	sql, args, err := NewSelect(b).Select("id", "name").From("user").
+		LeftJoin("class as c", expr.New("c.id=user.class_id"), "id", "name").
+		AndSelect("c", "name as class_name").
+		Where(expr.Op("user.age", "=", 13)).
+		AliasJoins().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT user.id,user.name,c.id AS c__id,c.name AS c__name,c.name as class_name FROM user LEFT JOIN class as c ON c.id=user.class_id WHERE user.age=?", sql)
+	assert.Equal(t, []interface{}{13}, args)
+
+	// the joined columns are not aliased by default
+	sql, _, err = NewSelect(b).Select("id").From("user").
+		LeftJoin("class as c", expr.New("c.id=user.class_id"), "name").
+		ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT id,name FROM user LEFT JOIN class as c ON c.id=user.class_id", sql)
+
+	sql, _, err = NewSelect(b).Select("id").From("user", "class").AndSelect("class", "name").AliasJoins().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT user.id,class.name FROM user, class", sql)
+
+	// the single table output is unchanged
+	sql, _, err = NewSelect(b).From("class").AndSelect("class", "name").AliasJoins().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT name FROM class", sql)
+
+	sql, _, err = NewSelect(b).AndSelect("class", "*").From("class").ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM class", sql)
+}
@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/utils"
+)
+
+// SoftDelete excludes the soft deleted rows from the statements.
+// A row is soft deleted when the deleted time column of its table is not NULL.
+type SoftDelete struct {
+	// Columns are the deleted time columns keyed by table, it is disabled if empty
+	Columns map[string]string
+	// Only selects the deleted rows of the statement tables instead of excluding them,
+	// the rows of the joined tables are always excluded.
+	Only bool
+}
+
+// cond return the condition of the table, the column is qualified by the alias if it is not empty.
+// table can contain the alias, e.g. "user u", empty is returned if the table is not soft deleted.
+func (sd SoftDelete) cond(table string, qualify bool, only bool) string {
+	fields := strings.Fields(table)
+	if len(fields) == 0 {
+		return ""
+	}
+	col, ok := sd.Columns[fields[0]]
+	if !ok || col == "" {
+		return ""
+	}
+	if qualify {
+		col = utils.AliasName(table) + "." + col
+	}
+	if only {
+		return col + " IS NOT NULL"
+	}
+	return col + " IS NULL"
+}
+
+// andScope concatenates the condition of a WHERE or ON clause with the scope using "AND",
+// the condition is enclosed in parentheses as it may contain "OR"
+func andScope(cond string, scope string) string {
+	if scope == "" {
+		return cond
+	}
+	if cond == "" {
+		return scope
+	}
+	return "(" + cond + ") AND " + scope
+}
+
+// scopeWhere adds the scope to the clause generated by Builder.Where
+func scopeWhere(where string, scope string) string {
+	if scope == "" {
+		return where
+	}
+	return "WHERE " + andScope(strings.TrimPrefix(where, "WHERE "), scope)
+}
+
+// scopeJoins return the joins whose ON clauses exclude the soft deleted rows of the joined tables,
+// the ON clauses are built into params in order.
+func (sd SoftDelete) scopeJoins(joins []expr.JoinInfo, params expr.Params) []expr.JoinInfo {
+	scoped := make([]expr.JoinInfo, len(joins))
+	for i, join := range joins {
+		scoped[i] = join
+		scope := sd.cond(join.Table, true, false)
+		if scope == "" {
+			continue
+		}
+		on := ""
+		if join.On != nil {
+			on = join.On.Build(params)
+		}
+		scoped[i].On = expr.New(andScope(on, scope))
+	}
+	return scoped
+}
@@ -2,8 +2,13 @@ package builder
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/schema"
 	"github.com/rumis/seal/utils"
 )
 
@@ -15,6 +20,16 @@ type Update struct {
 	table string
 	val   map[string]interface{}
 	where expr.Expr
+	all   bool
+	sd    SoftDelete
+	ver   *version
+
+	// at fills the updated time columns, it is disabled if nil
+	at     *autoTime
+	filter columnFilter
+
+	// err is the first error occurred when setting the value, it is returned by ToSql
+	err error
 }
 
 // NewUpdate 创建新更新器
@@ -34,17 +49,97 @@ func (u *Update) Table(table string) *Update {
 // type of val can be map[string]interface{}, struct
 func (u *Update) Value(val interface{}) *Update {
 	if v, ok := val.(map[string]interface{}); ok {
+		if u.at != nil || u.filter.enabled() {
+			v = copyMap(v)
+			u.prepare(v, nil)
+		}
 		u.val = v
 		return u
 	}
 	v, err := utils.Struct2Map(val)
 	if err != nil {
+		u.setErr(fmt.Errorf("Update.Value: unsupported value type %T: %w", val, err))
 		return u
 	}
+	stripRelations([]map[string]interface{}{v}, val)
+	u.prepare(v, val)
 	u.val = v
+	if s, err := schema.Parse(val); err == nil && s.Version != nil {
+		u.Version(s.Version.Column, s.Version.Interface(reflect.Indirect(reflect.ValueOf(val))))
+	}
+	return u
+}
+
+// version is the version column of the optimistic locking
+type version struct {
+	col     string
+	current interface{}
+}
+
+// Version enables the optimistic locking, the statement only updates the rows whose column equals current,
+// and increments the column. It is called by Value if the struct value has a field tagged with version.
+func (u *Update) Version(col string, current interface{}) *Update {
+	u.ver = &version{col: col, current: current}
 	return u
 }
 
+// Versioned reports whether the optimistic locking is enabled
+func (u *Update) Versioned() bool {
+	return u.ver != nil
+}
+
+// AutoTime sets the updated time columns to now when the value is set, and removes the created time columns
+// which are zero in the struct value. The columns are declared by ts and the autocreatetime and autoupdatetime tags.
+// It must be called before the value is set.
+func (u *Update) AutoTime(now AutoTimeFunc, ts options.TableTimestamps) *Update {
+	u.at = &autoTime{now: now, ts: ts}
+	return u
+}
+
+// Only sets only the columns of the value, the zero fields of the struct value dropped by omitempty are set if they are selected.
+// It is an error if a column is not in the value. It must be called before the value is set.
+func (u *Update) Only(cols ...string) *Update {
+	if u.val != nil {
+		u.setErr(errors.New("Update.Only: must be called before the value is set"))
+	}
+	u.filter.only = append(u.filter.only, cols...)
+	return u
+}
+
+// Omit drops the columns from the value, the time columns filled by AutoTime are dropped too.
+// It is an error if a column is not in the value. It must be called before the value is set.
+func (u *Update) Omit(cols ...string) *Update {
+	if u.val != nil {
+		u.setErr(errors.New("Update.Omit: must be called before the value is set"))
+	}
+	u.filter.omit = append(u.filter.omit, cols...)
+	return u
+}
+
+// prepare applies the column filter and fills the time columns of the value converted from src, src is nil if the value is a map
+func (u *Update) prepare(v map[string]interface{}, src interface{}) {
+	if u.at == nil && !u.filter.enabled() {
+		return
+	}
+	row := newValueRows([]map[string]interface{}{v}, src)[0]
+	if err := u.filter.apply(row, u.at.known(row)); err != nil {
+		u.setErr(fmt.Errorf("Update.Value: %w", err))
+	}
+	if u.at == nil {
+		return
+	}
+	if err := u.at.fillUpdate(row, u.filter); err != nil {
+		u.setErr(fmt.Errorf("Update.Value: fill the time columns: %w", err))
+	}
+}
+
+// setErr records the error if it is the first one
+func (u *Update) setErr(err error) {
+	if u.err == nil {
+		u.err = err
+	}
+}
+
 // Where specifies the WHERE condition.
 func (u *Update) Where(e expr.Expr) *Update {
 	if u.where == nil {
@@ -72,20 +167,54 @@ func (u *Update) OrWhere(e expr.Expr) *Update {
 	return u
 }
 
+// All allows the statement to update all the rows when the WHERE clause is not set or always true
+func (u *Update) All() *Update {
+	u.all = true
+	return u
+}
+
+// SoftDelete excludes the soft deleted rows, or only updates them if sd.Only is set
+func (u *Update) SoftDelete(sd SoftDelete) *Update {
+	u.sd = sd
+	return u
+}
+
+// TableName return the table of the statement
+func (u *Update) TableName() string {
+	return u.table
+}
+
 // ToSql build the sql clauses and params
 func (u *Update) ToSql() (string, []interface{}, error) {
+	if u.err != nil {
+		return "", nil, u.err
+	}
 	if u.val == nil {
 		return "", nil, errors.New("update value not set")
 	}
 	if u.table == "" {
 		return "", nil, errors.New("table name not set")
 	}
-	if u.where == nil {
-		return "", nil, errors.New("update should have a where clauses")
-	}
 	params := expr.Params{}
 
-	sql := u.b.Update(u.table, u.val, params) + " " + u.b.Where(u.where, params)
+	val := u.val
+	if u.ver != nil {
+		val = copyMap(val)
+		val[u.ver.col] = expr.New(u.ver.col + "+1")
+	}
+	sql := u.b.Update(u.table, val, params)
+	where := u.b.Where(u.where, params)
+	if err := checkWhere("update", u.table, u.where != nil, where, u.all); err != nil {
+		return "", nil, err
+	}
+	var scopes []string
+	if u.ver != nil {
+		scopes = append(scopes, expr.Op(u.ver.col, "=", u.ver.current).Build(params))
+	}
+	if scope := u.sd.cond(u.table, false, u.sd.Only); scope != "" {
+		scopes = append(scopes, scope)
+	}
+	sql += " " + scopeWhere(where, strings.Join(scopes, " AND "))
 
 	return utils.ReplacePlaceHolders(sql, u.b.Placeholder(), params)
 }
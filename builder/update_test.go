@@ -2,8 +2,10 @@ package builder
 
 import (
 	"testing"
+	"time"
 
 	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -30,8 +32,8 @@ func TestUpdateStruct(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	assert.Equal(t, "UPDATE student SET name=?, age=? WHERE age=?", sql)
-	assert.Equal(t, []interface{}{"murong", 13, 13}, params)
+	assert.Equal(t, "UPDATE student SET age=?, name=? WHERE age=?", sql)
+	assert.Equal(t, []interface{}{13, "murong", 13}, params)
 
 	// where and
 	u1 := NewUpdate(b)
@@ -124,7 +126,129 @@ func TestUpdateMap(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	assert.Equal(t, "UPDATE student SET name=?, age=? WHERE age=?", sql)
-	assert.Equal(t, []interface{}{"murong", 13, 13}, params)
+	assert.Equal(t, "UPDATE student SET age=?, name=? WHERE age=?", sql)
+	assert.Equal(t, []interface{}{13, "murong", 13}, params)
 
 }
+
+func TestUpdateError(t *testing.T) {
+
+	b := &BuilderStandard{}
+	_, _, err := NewUpdate(b).Table("student").Value([]string{"murong"}).Where(expr.StandardExp{
+		Col:   "id",
+		Op:    "=",
+		Value: 1,
+	}).ToSql()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Update.Value: unsupported value type []string")
+}
+
+func TestUpdateSafety(t *testing.T) {
+
+	b := &BuilderStandard{}
+
+	_, _, err := NewUpdate(b).Table("student").Value(map[string]interface{}{"age": 1}).ToSql()
+	assert.EqualError(t, err, "unsafe update on student: no WHERE clause, call All() to update all rows")
+
+	_, _, err = NewUpdate(b).Table("student").Value(map[string]interface{}{"age": 1}).Where(expr.NotIn("id")).ToSql()
+	assert.EqualError(t, err, "unsafe update on student: WHERE clause is always true, call All() to update all rows")
+
+	sql, args, err := NewUpdate(b).Table("student").Value(map[string]interface{}{"age": 1}).Where(expr.NotIn("id")).All().ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET age=? ", sql)
+	assert.Equal(t, []interface{}{1}, args)
+}
+
+func TestUpdateAutoTime(t *testing.T) {
+
+	b := &BuilderStandard{}
+	now := func() (interface{}, error) { return "2021-10-01 08:00:00", nil }
+	where := expr.StandardExp{Col: "id", Op: "=", Value: 1}
+
+	// struct tags, the zero created time is not updated
+	type student struct {
+		Name       string    `seal:"name"`
+		CreateTime time.Time `seal:"create_time,autocreatetime"`
+		UpdateTime time.Time `seal:"update_time,autoupdatetime"`
+	}
+	sql, args, err := NewUpdate(b).Table("student").AutoTime(now, options.TableTimestamps{}).
+		Value(student{Name: "murong", UpdateTime: time.Now()}).Where(where).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET name=?, update_time=? WHERE id=?", sql)
+	assert.Equal(t, []interface{}{"murong", "2021-10-01 08:00:00", 1}, args)
+
+	// table option, the column set in the map is kept
+	ts := options.TableTimestamps{Create: "create_time", Update: "update_time"}
+	sql, args, err = NewUpdate(b).Table("student").AutoTime(now, ts).
+		Value(map[string]interface{}{"name": "murong"}).Where(where).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET name=?, update_time=? WHERE id=?", sql)
+	assert.Equal(t, []interface{}{"murong", "2021-10-01 08:00:00", 1}, args)
+
+	sql, args, err = NewUpdate(b).Table("student").AutoTime(now, ts).
+		Value(map[string]interface{}{"update_time": "2021-01-01 00:00:00"}).Where(where).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET update_time=? WHERE id=?", sql)
+	assert.Equal(t, []interface{}{"2021-01-01 00:00:00", 1}, args)
+}
+
+func TestUpdateSoftDelete(t *testing.T) {
+
+	b := &BuilderStandard{}
+	sd := SoftDelete{Columns: map[string]string{"student": "deleted_at"}}
+
+	sql, args, err := NewUpdate(b).Table("student").Value(map[string]interface{}{"age": 14}).
+		Where(expr.Op("age", "=", 13)).SoftDelete(sd).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET age=? WHERE (age=?) AND deleted_at IS NULL", sql)
+	assert.Equal(t, []interface{}{14, 13}, args)
+}
+
+func TestUpdateVersion(t *testing.T) {
+
+	b := &BuilderStandard{}
+
+	sql, args, err := NewUpdate(b).Table("student").Value(map[string]interface{}{"age": 14}).
+		Where(expr.Op("id", "=", 1)).OrWhere(expr.Op("id", "=", 2)).Version("version", 3).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET age=?, version=version+1 WHERE (id=? OR id=?) AND version=?", sql)
+	assert.Equal(t, []interface{}{14, 1, 2, 3}, args)
+
+	// struct tag
+	type student struct {
+		Name    string `seal:"name"`
+		Version int    `seal:"version,version"`
+	}
+	u := NewUpdate(b).Table("student").Value(&student{Name: "murong", Version: 5}).Where(expr.Op("id", "=", 1))
+	sql, args, err = u.ToSql()
+	assert.Nil(t, err)
+	assert.True(t, u.Versioned())
+	assert.Equal(t, "UPDATE student SET name=?, version=version+1 WHERE (id=?) AND version=?", sql)
+	assert.Equal(t, []interface{}{"murong", 1, 5}, args)
+}
+
+func TestUpdateOnlyOmit(t *testing.T) {
+
+	b := &BuilderStandard{}
+	where := expr.Op("id", "=", 1)
+	type student struct {
+		Name string `seal:"name"`
+		Age  int    `seal:"age,omitempty"`
+	}
+
+	sql, args, err := NewUpdate(b).Table("student").Only("age").Value(student{Name: "murong"}).Where(where).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET age=? WHERE id=?", sql)
+	assert.Equal(t, []interface{}{0, 1}, args)
+
+	// the omitted time column is not filled
+	now := func() (interface{}, error) { return "2021-10-01 08:00:00", nil }
+	sql, args, err = NewUpdate(b).Table("student").AutoTime(now, options.TableTimestamps{Update: "update_time"}).
+		Omit("age", "update_time").Value(map[string]interface{}{"name": "murong", "age": 13}).Where(where).ToSql()
+	assert.Nil(t, err)
+	assert.Equal(t, "UPDATE student SET name=? WHERE id=?", sql)
+	assert.Equal(t, []interface{}{"murong", 1}, args)
+
+	_, _, err = NewUpdate(b).Table("student").Only("class").Value(student{Name: "murong"}).Where(where).ToSql()
+	assert.EqualError(t, err, "Update.Value: unknown column class")
+}
@@ -0,0 +1,84 @@
+package seal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateChanged(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stmts []string
+	var args [][]interface{}
+	db, err := Open("sqlite3", dbfile, options.WithInterceptor(func(ctx context.Context, info *options.QueryInfo, next options.QueryHandler) (options.QueryResult, error) {
+		stmts = append(stmts, info.SQL)
+		args = append(args, info.Args)
+		return next(ctx, info)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	u := userModel{Name: randString(8), Age: 13, Class: 2}
+	assert.Nil(t, db.Model(&u).Create(ctx))
+
+	// only the changed fields are updated, including the zero values
+	before := u
+	u.Age = 0
+	stmts = nil
+	cnt, err := db.UpdateChanged(ctx, "user", before, &u, Eq("id", u.ID))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+	assert.Equal(t, []string{"UPDATE user SET age=? WHERE id=?"}, stmts)
+
+	// nothing is executed if nothing is changed
+	stmts = nil
+	cnt, err = db.UpdateChanged(ctx, "user", u, u, Eq("id", u.ID))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), cnt)
+	assert.Empty(t, stmts)
+
+	// model
+	var found userModel
+	m := db.Model(&found)
+	assert.Nil(t, m.Find(ctx, u.ID))
+	assert.Equal(t, 0, found.Age)
+	found.Class = 0
+	assert.Equal(t, map[string]interface{}{"class_id": 0}, m.Changes())
+	stmts = nil
+	assert.Nil(t, m.Save(ctx))
+	assert.Nil(t, m.Save(ctx))
+	assert.Equal(t, []string{"UPDATE user SET class_id=? WHERE id=?"}, stmts)
+
+	var saved userModel
+	assert.Nil(t, db.Model(&saved).Find(ctx, u.ID))
+	assert.Equal(t, found, saved)
+
+	// the changed fields are encoded as the full struct
+	var timed userTimeModel
+	m = db.Model(&timed)
+	assert.Nil(t, m.Find(ctx, u.ID))
+	timed.CreateTime = time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+	stmts, args = nil, nil
+	assert.Nil(t, m.Save(ctx))
+	assert.Equal(t, []string{"UPDATE user SET create_time=? WHERE id=?"}, stmts)
+	assert.Equal(t, []interface{}{"2021-10-01 08:00:00", u.ID}, args[0])
+}
+
+type userTimeModel struct {
+	ID         int64     `seal:"id,pk,autoincr"`
+	CreateTime time.Time `seal:"create_time,omitempty"`
+}
+
+func (userTimeModel) TableName() string {
+	return "user"
+}
@@ -0,0 +1,76 @@
+package seal
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+// captureExecutor records the statements sent to the database
+type captureExecutor struct {
+	*sql.DB
+	stmts []string
+}
+
+func (e *captureExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.stmts = append(e.stmts, query)
+	return e.DB.ExecContext(ctx, query, args...)
+}
+
+func (e *captureExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	e.stmts = append(e.stmts, query)
+	return e.DB.QueryContext(ctx, query, args...)
+}
+
+func TestSQLComment(t *testing.T) {
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdb, err := sql.Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdb.Close()
+
+	cfg := options.DefaultSealOptions()
+	options.WithSQLComment(true)(cfg)
+	e := &captureExecutor{DB: sdb}
+	q := query.NewQuery(builder.NewSqliteBuilder(), e, cfg)
+
+	ctx := context.WithValue(context.Background(), options.DefaultTraceKey, "abc123")
+	var id int64
+	if err := q.Insert("class").Value(Class{Name: "comment"}).Exec(ctx, &id); err != nil {
+		t.Fatal(err)
+	}
+	var cnt int64
+	if err := q.Count("id").From("class").Query(ctx).Agg(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(1), cnt)
+
+	pq, err := q.Select("name").From("class").Where(Eq("id", Param("id"))).Prepare(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pq.Close()
+	row, err := pq.Bind(map[string]interface{}{"id": id}).Query(ctx).OneMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "comment", row["name"])
+
+	if !assert.Len(t, e.stmts, 3) {
+		t.FailNow()
+	}
+	// the dir of caller depends on where the repo is checked out
+	assert.Regexp(t, `^INSERT INTO class \(name\) VALUES \(\?\) /\* trace_id=abc123, caller=[\w-]+/comment_test.go:\d+ \*/$`, e.stmts[0])
+	assert.Regexp(t, `^SELECT COUNT\(id\) AS agg_count FROM class /\* trace_id=abc123, caller=[\w-]+/comment_test.go:\d+ \*/$`, e.stmts[1])
+	// the prepared statements are commented too
+	assert.Regexp(t, `^SELECT name FROM class WHERE id=\? /\* trace_id=abc123, caller=[\w-]+/comment_test.go:\d+ \*/$`, e.stmts[2])
+}
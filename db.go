@@ -1,8 +1,13 @@
 package seal
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"sync"
+	"time"
 
+	"github.com/rumis/seal/options"
 	"github.com/rumis/seal/query"
 )
 
@@ -11,21 +16,102 @@ import (
 type DB struct {
 	query.Query
 
-	sqlDB *sql.DB
+	// sqlDB is nil if the DB is opened with an executor which can not begin transactions
+	sqlDB dbExecutor
+	// stopStats stops reporting the stats of the connection pool
+	stopStats func()
 }
 
+// dbExecutor is the executor which owns a connection pool, eg. *sql.DB
+type dbExecutor interface {
+	query.Executor
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	Stats() sql.DBStats
+	Close() error
+}
+
+// txWrapper is implemented by the executors which wrap the executor of their transactions, eg. sealtest.Golden
+type txWrapper interface {
+	WrapTx(tx *sql.Tx) query.Executor
+}
+
+// errNoTx is returned when beginning a transaction on a DB opened with a plain executor
+var errNoTx = errors.New("the executor of db does not support transactions")
+
 // Begin starts a transaction.
 func (db *DB) Begin() (*Tx, error) {
-	var tx *sql.Tx
-	var err error
-	tx, err = db.sqlDB.Begin()
+	return db.BeginTx(context.Background(), nil)
+}
+
+// BeginTx starts a transaction with the given context and options.
+// The context is passed to the commit and rollback hooks of the transaction.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if db.sqlDB == nil {
+		return nil, errNoTx
+	}
+	tx, err := db.sqlDB.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{query.NewQuery(db.Builder(), tx, db.Options()), tx}, nil
+	var e query.Executor = tx
+	if w, ok := db.sqlDB.(txWrapper); ok {
+		e = w.WrapTx(tx)
+	}
+	return &Tx{Query: db.WithExecutor(e), tx: tx, ctx: ctx}, nil
+}
+
+// Transaction runs fn in a transaction.
+// The transaction is committed if fn returns nil, otherwise it is rolled back.
+func (db *DB) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return runTx(tx, fn)
+}
+
+// Stats returns the stats of the connection pool, it is empty if the db has no connection pool
+func (db *DB) Stats() sql.DBStats {
+	if db.sqlDB == nil {
+		return sql.DBStats{}
+	}
+	return db.sqlDB.Stats()
 }
 
 // Close close the db
 func (db *DB) Close() error {
+	if db.stopStats != nil {
+		db.stopStats()
+	}
+	if c := db.StmtCache(); c != nil {
+		c.Close()
+	}
+	if db.sqlDB == nil {
+		return nil
+	}
 	return db.sqlDB.Close()
 }
+
+// reportPoolStats reports the stats of the connection pool to the collector every interval
+// the returned func stops the reporting
+func reportPoolStats(db dbExecutor, c options.MetricsCollector, interval time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.PoolStats(db.Stats())
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package seal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dry, e := DryRun(db)
+	defer dry.Close()
+
+	var id, cnt int64
+	err = dry.Insert("class").Value(Class{Name: "O'Neil"}).Exec(ctx, &id)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), id)
+
+	e.SetResult(0, 3)
+	err = dry.Transaction(ctx, func(tx *Tx) error {
+		if err := tx.Update("class").Value(map[string]interface{}{"name": "x"}).Where(Eq("id", 1)).Exec(ctx, &cnt); err != nil {
+			return err
+		}
+		return tx.Delete("class").Where(In("id", 2, 3)).Exec(ctx, &cnt)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), cnt)
+
+	rows, err := dry.Select("id").From("class").Query(ctx).AllMap()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 0)
+
+	assert.Equal(t, "INSERT INTO class (name) VALUES (?)", e.Statements()[0].SQL)
+	assert.Equal(t, []interface{}{"O'Neil"}, e.Statements()[0].Args)
+	assert.Equal(t, `INSERT INTO class (name) VALUES ('O''Neil');
+BEGIN;
+UPDATE class SET name='x' WHERE id=1;
+DELETE FROM class WHERE id IN (2, 3);
+COMMIT;
+SELECT id FROM class;
+`, e.Script())
+
+	// nothing is written to the database
+	var total int64
+	err = db.Count("id").From("class").Query(ctx).Agg(&total)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), total)
+
+	e.Reset()
+	assert.Len(t, e.Statements(), 0)
+}
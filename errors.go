@@ -0,0 +1,30 @@
+package seal
+
+import (
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/query"
+	"github.com/rumis/seal/sqlerr"
+)
+
+// the kinds of the normalized driver errors, see package sqlerr
+var (
+	ErrUniqueViolation     = sqlerr.ErrUniqueViolation
+	ErrForeignKeyViolation = sqlerr.ErrForeignKeyViolation
+	ErrNotNullViolation    = sqlerr.ErrNotNullViolation
+	ErrDeadlock            = sqlerr.ErrDeadlock
+	ErrLockTimeout         = sqlerr.ErrLockTimeout
+	// ErrNoRows is returned by Model.Find only, OneMap and OneStruct return an empty result instead
+	ErrNoRows = sqlerr.ErrNoRows
+)
+
+// Error is the normalized driver error which exposes the constraint and column
+type Error = sqlerr.Error
+
+// ErrUnsafeStatement is matched by errors.Is for the statements rejected by the safety guard
+var ErrUnsafeStatement = builder.ErrUnsafeStatement
+
+// SafetyError reports the statement rejected by the safety guard
+type SafetyError = builder.SafetyError
+
+// ErrStaleObject is returned by the versioned update which affects no rows
+var ErrStaleObject = query.ErrStaleObject
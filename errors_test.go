@@ -0,0 +1,92 @@
+package seal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/sqlerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorTranslation(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile+"?_foreign_keys=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE account (
+		id       INTEGER PRIMARY KEY AUTOINCREMENT,
+		email    VARCHAR (64) NOT NULL UNIQUE,
+		class_id INTEGER REFERENCES class (id)
+	)`).RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var id int64
+	email := randString(12)
+	err = db.Insert("account").Value(map[string]interface{}{"email": email}).Exec(ctx, &id)
+	assert.Nil(t, err)
+
+	err = db.Insert("account").Value(map[string]interface{}{"email": email}).Exec(ctx, &id)
+	assert.True(t, errors.Is(err, ErrUniqueViolation))
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "sqlite3", e.Driver)
+	assert.Equal(t, "account", e.Table)
+	assert.Equal(t, "email", e.Column)
+
+	err = db.Insert("account").Value(map[string]interface{}{"email": nil}).Exec(ctx, &id)
+	assert.True(t, errors.Is(err, ErrNotNullViolation))
+
+	err = db.Insert("account").Value(map[string]interface{}{"email": randString(12), "class_id": 1 << 30}).Exec(ctx, &id)
+	assert.True(t, errors.Is(err, ErrForeignKeyViolation))
+}
+
+func TestErrorTranslationDriver(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdb, err := sql.Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the errors are translated by the driver name instead of the dialect of the standard builder
+	sqlerr.Register("sqlite3-custom", func(err error) *sqlerr.Error {
+		return &sqlerr.Error{Kind: ErrLockTimeout}
+	})
+	db, err := OpenWithDB(sdb, builder.NewStandardBuilder(), options.WithDriver("sqlite3-custom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Select("id").From("missing").Query(ctx).AllMap()
+	assert.True(t, errors.Is(err, ErrLockTimeout))
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "sqlite3-custom", e.Driver)
+	assert.Equal(t, "sqlite3-custom", db.Driver())
+
+	db, err = OpenWithDB(sdb, builder.NewStandardBuilder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Select("id").From("missing").Query(ctx).AllMap()
+	assert.False(t, errors.Is(err, ErrLockTimeout))
+	assert.Equal(t, "standard", db.Driver())
+}
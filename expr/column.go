@@ -21,3 +21,45 @@ func (e ColumnExp) Build(params Params) string {
 	}
 	return strings.Join(column, ",")
 }
+
+// PrefixSeparator separates the table and the column in the aliases of PrefixColumnExp
+const PrefixSeparator = "__"
+
+// PrefixColumnExp represents the columns of a table which are aliased with the table as prefix,
+// e.g. name of class is built into "class.name AS class__name".
+// The columns which are not plain names (aliased, "*", functions...) are only qualified with the table.
+type PrefixColumnExp struct {
+	Columns []string
+	Table   string
+}
+
+// Build converts an expression into a SQL fragment.
+func (e PrefixColumnExp) Build(params Params) string {
+	column := make([]string, 0, len(e.Columns))
+	for _, col := range e.Columns {
+		if e.Table == "" {
+			column = append(column, col)
+			continue
+		}
+		if isPlainColumn(col) {
+			col = e.Table + "." + col + " AS " + e.Table + PrefixSeparator + col
+		} else {
+			col = e.Table + "." + col
+		}
+		column = append(column, col)
+	}
+	return strings.Join(column, ",")
+}
+
+// isPlainColumn reports whether the column is a plain name which consists of letters, digits and underscores
+func isPlainColumn(col string) bool {
+	if col == "" {
+		return false
+	}
+	for _, c := range col {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
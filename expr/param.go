@@ -0,0 +1,13 @@
+package expr
+
+// NamedParam represents a placeholder whose value is bound when a prepared query is executed.
+// It can be used as the value of any expression which accept params, eg. Op, In, Between.
+// A param in In stands for one value, it can not be expanded by a slice when bound.
+type NamedParam struct {
+	Name string
+}
+
+// Param generates a named placeholder
+func Param(name string) NamedParam {
+	return NamedParam{Name: name}
+}
@@ -5,6 +5,9 @@ go 1.16
 require (
 	github.com/mattn/go-sqlite3 v1.14.12
 	github.com/rumis/mapstructure v1.4.7
-	github.com/satori/go.uuid v1.2.0 // indirect
+	github.com/satori/go.uuid v1.2.0
 	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,82 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hookedClass struct {
+	ID   int64  `seal:"id,omitempty"`
+	Name string `seal:"name"`
+
+	Found bool `seal:"-"`
+}
+
+func (c *hookedClass) BeforeInsert(ctx context.Context) error {
+	if c.Name == "" {
+		return errors.New("class name is required")
+	}
+	c.Name = strings.ToUpper(c.Name)
+	return nil
+}
+
+func (c *hookedClass) BeforeUpdate(ctx context.Context) error {
+	return c.BeforeInsert(ctx)
+}
+
+func (c *hookedClass) AfterFind(ctx context.Context) error {
+	c.Found = true
+	return nil
+}
+
+func TestHooks(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// the value is normalized by BeforeInsert
+	name := strings.ToLower(randString(8))
+	var id int64
+	err = db.Insert("class").Value(hookedClass{Name: name}).Exec(ctx, &id)
+	assert.Nil(t, err)
+
+	// the error aborts the insert
+	var cnt int64
+	assert.Nil(t, db.Count("id").From("class").Query(ctx).Agg(&cnt))
+	err = db.Insert("class").Values([]hookedClass{{Name: "a"}, {}}).Exec(ctx, &id)
+	assert.EqualError(t, err, "class name is required")
+	var after int64
+	assert.Nil(t, db.Count("id").From("class").Query(ctx).Agg(&after))
+	assert.Equal(t, cnt, after)
+
+	// AfterFind is called on the decoded structs
+	var classes []hookedClass
+	err = db.Select("id", "name").From("class").Where(Eq("name", strings.ToUpper(name))).Query(ctx).AllStruct(&classes)
+	assert.Nil(t, err)
+	assert.Len(t, classes, 1)
+	assert.True(t, classes[0].Found)
+
+	var c hookedClass
+	assert.Nil(t, db.Select("id", "name").From("class").Where(Eq("name", strings.ToUpper(name))).Query(ctx).OneStruct(&c))
+	assert.True(t, c.Found)
+
+	// BeforeUpdate
+	err = db.Update("class").Value(&hookedClass{}).Where(Eq("id", c.ID)).Exec(ctx, &cnt)
+	assert.EqualError(t, err, "class name is required")
+	err = db.Update("class").Value(&hookedClass{Name: "b"}).Where(Eq("id", c.ID)).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Select("id", "name").From("class").Where(Eq("id", c.ID)).Query(ctx).OneStruct(&c))
+	assert.Equal(t, "B", c.Name)
+}
@@ -0,0 +1,121 @@
+package seal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeResult is a sql.Result returned by interceptors
+type fakeResult struct {
+	lastId, affected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) { return r.lastId, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.affected, nil }
+
+func TestInterceptor(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errVeto := errors.New("veto")
+	infos := make([]options.QueryInfo, 0)
+	durations := make([]time.Duration, 0)
+	db, err := Open("sqlite3", dbfile,
+		// record
+		options.WithInterceptor(func(ctx context.Context, info *options.QueryInfo, next options.QueryHandler) (options.QueryResult, error) {
+			sTime := time.Now()
+			res, err := next(ctx, info)
+			durations = append(durations, time.Since(sTime))
+			infos = append(infos, *info)
+			return res, err
+		}),
+		// veto and short-circuit
+		options.WithInterceptor(func(ctx context.Context, info *options.QueryInfo, next options.QueryHandler) (options.QueryResult, error) {
+			if info.Op == options.OpDelete {
+				return options.QueryResult{}, errVeto
+			}
+			if info.Op == options.OpUpdate && info.Table == "audit" {
+				return options.QueryResult{Result: fakeResult{affected: 42}}, nil
+			}
+			return next(ctx, info)
+		}),
+		// rewrite
+		options.WithInterceptor(func(ctx context.Context, info *options.QueryInfo, next options.QueryHandler) (options.QueryResult, error) {
+			if info.Op == options.OpSelect {
+				info.SQL = strings.Replace(info.SQL, "FROM class", "FROM class WHERE name<>'hidden'", 1)
+			}
+			return next(ctx, info)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var id int64
+	for _, name := range []string{"visible", "hidden"} {
+		if err := db.Insert("class").Value(Class{Name: name}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rows, err := db.Select("name").From("class").Query(ctx).AllMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []map[string]interface{}{{"name": "visible"}}, rows)
+
+	var cnt int64
+	err = db.Delete("class").Where(Eq("name", "visible")).Exec(ctx, &cnt)
+	assert.ErrorIs(t, err, errVeto)
+
+	err = db.Update("audit").Value(map[string]interface{}{"name": "x"}).Where(Eq("id", 1)).Exec(ctx, &cnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(42), cnt)
+
+	var res sql.Result = db.ExecContext(ctx, "UPDATE class SET name=? WHERE name=?", "renamed", "visible")
+	cnt, err = res.RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(1), cnt)
+
+	ops := make([]options.Operation, 0, len(infos))
+	for _, info := range infos {
+		ops = append(ops, info.Op)
+	}
+	assert.Equal(t, []options.Operation{
+		options.OpInsert, options.OpInsert, options.OpSelect, options.OpDelete, options.OpUpdate, options.OpExec,
+	}, ops)
+	assert.Equal(t, "class", infos[0].Table)
+	assert.Equal(t, []interface{}{"visible"}, infos[0].Args)
+	assert.Equal(t, "SELECT name FROM class WHERE name<>'hidden'", infos[2].SQL)
+	assert.Equal(t, "audit", infos[4].Table)
+	assert.Len(t, durations, len(infos))
+
+	// the rewritten sql of a prepared select can be executed
+	pq, err := db.Select("name").From("class").Prepare(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pq.Close()
+	_, err = pq.Bind(nil).Exec(ctx).RowsAffected()
+	assert.Nil(t, err)
+	rows, err = pq.Bind(nil).Query(ctx).AllMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []map[string]interface{}{{"name": "renamed"}}, rows)
+}
@@ -0,0 +1,189 @@
+// Package fakedriver is an in-memory database/sql driver which hands all the calls to a Handler,
+// it backs the dry-run executor and the sealtest mock.
+package fakedriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+)
+
+// Handler receives the statements and the transaction calls of the connections
+type Handler interface {
+	// Begin starts a transaction
+	Begin() error
+	// Commit commits the transaction
+	Commit() error
+	// Rollback aborts the transaction
+	Rollback() error
+	// Exec executes the statement
+	Exec(query string, args []driver.NamedValue) (driver.Result, error)
+	// Query queries the statement
+	Query(query string, args []driver.NamedValue) (driver.Rows, error)
+}
+
+// Open return a db whose connections call the handler
+func Open(h Handler) *sql.DB {
+	return sql.OpenDB(connector{h: h})
+}
+
+// Args return the values of the named args
+func Args(args []driver.NamedValue) []interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+	vals := make([]interface{}, len(args))
+	for i, arg := range args {
+		vals[i] = arg.Value
+	}
+	return vals
+}
+
+// NewResult generate the result of an execution
+func NewResult(lastInsertId int64, rowsAffected int64) driver.Result {
+	return result{lastInsertId, rowsAffected}
+}
+
+// NewRows generate the result set of the columns and rows
+func NewRows(cols []string, rows [][]driver.Value) driver.Rows {
+	return &rowsIter{cols: cols, rows: rows}
+}
+
+// connector opens the connections of the handler
+type connector struct {
+	h Handler
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return conn(c), nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+// fakeDriver can only be opened by the connector
+type fakeDriver struct{}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, driver.ErrSkip
+}
+
+// conn hands all the calls to the handler
+type conn struct {
+	h Handler
+}
+
+func (c conn) Prepare(query string) (driver.Stmt, error) {
+	return stmt{h: c.h, query: query}, nil
+}
+
+func (c conn) Close() error {
+	return nil
+}
+
+func (c conn) Begin() (driver.Tx, error) {
+	if err := c.h.Begin(); err != nil {
+		return nil, err
+	}
+	return tx(c), nil
+}
+
+func (c conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c.Begin()
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.h.Exec(query, args)
+}
+
+func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.h.Query(query, args)
+}
+
+// CheckNamedValue passes the args to the handler as they are
+func (c conn) CheckNamedValue(nv *driver.NamedValue) error {
+	return nil
+}
+
+// tx hands the end of the transaction to the handler
+type tx struct {
+	h Handler
+}
+
+func (t tx) Commit() error {
+	return t.h.Commit()
+}
+
+func (t tx) Rollback() error {
+	return t.h.Rollback()
+}
+
+// stmt hands the statement to the handler each time it is executed
+type stmt struct {
+	h     Handler
+	query string
+}
+
+func (s stmt) Close() error {
+	return nil
+}
+
+func (s stmt) NumInput() int {
+	return -1
+}
+
+func (s stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.h.Exec(s.query, args)
+}
+
+func (s stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.h.Query(s.query, args)
+}
+
+// result is the result of an execution
+type result struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+func (r result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// rowsIter iterates the rows of a result set
+type rowsIter struct {
+	cols []string
+	rows [][]driver.Value
+	pos  int
+}
+
+func (r *rowsIter) Columns() []string {
+	return r.cols
+}
+
+func (r *rowsIter) Close() error {
+	return nil
+}
+
+func (r *rowsIter) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
@@ -0,0 +1,117 @@
+package seal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type joinedClass struct {
+	ID   int64  `seal:"id"`
+	Name string `seal:"name"`
+}
+
+type userWithClass struct {
+	ID    int64        `seal:"id"`
+	Name  string       `seal:"name"`
+	Class *joinedClass `seal:"class,prefix=class_"`
+}
+
+func TestJoinNested(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	className := randString(8)
+	var classID int64
+	if err := db.Insert("class").Value(Class{Name: className}).Exec(ctx, &classID); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{randString(8), randString(9)}
+	var userIds []interface{}
+	for i, name := range names {
+		var id int64
+		// the second user has no class
+		if err := db.Insert("user").Value(User{Name: name, Age: 13, Class: int(classID) * (1 - i)}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+		userIds = append(userIds, id)
+	}
+
+	// the columns of the joined table are aliased with the table as prefix
+	rows, err := db.Select("id", "name").From("user").AliasJoins().
+		LeftJoin("class", StaticEq("class.id", "user.class_id"), "id", "name").
+		Where(In("user.id", userIds...)).
+		OrderBy("user.id ASC").
+		Query(ctx).AllMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, rows, 2)
+	assert.Equal(t, names[0], rows[0]["name"])
+	assert.Equal(t, className, rows[0]["class__name"])
+	assert.Equal(t, classID, rows[0]["class__id"])
+
+	// the nested struct is nil for the missing side of the LEFT JOIN
+	var users []userWithClass
+	err = db.Select("id", "name").From("user").AliasJoins().
+		LeftJoin("class", StaticEq("class.id", "user.class_id"), "id", "name").
+		Where(In("user.id", userIds...)).
+		OrderBy("user.id ASC").
+		Query(ctx).AllStruct(&users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, users, 2)
+	assert.Equal(t, userIds[0], users[0].ID)
+	assert.Equal(t, names[0], users[0].Name)
+	assert.Equal(t, &joinedClass{ID: classID, Name: className}, users[0].Class)
+	assert.Equal(t, names[1], users[1].Name)
+	assert.Nil(t, users[1].Class)
+
+	// the columns starting with the prefix are decoded into the nested struct, except the columns of the model
+	var user struct {
+		ID      int64       `seal:"id"`
+		ClassID int64       `seal:"class_id"`
+		Class   joinedClass `seal:"class,prefix=class_"`
+	}
+	err = db.Select("id", "class_id").From("user").AliasJoins().
+		InnerJoin("class", StaticEq("class.id", "user.class_id"), "name as class_name").
+		Where(Eq("user.id", userIds[0])).
+		Query(ctx).OneStruct(&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, classID, user.ClassID)
+	assert.Equal(t, joinedClass{Name: className}, user.Class)
+
+	// the nested fields can not be decoded from the joins which are not aliased
+	err = db.Select("age").From("user").
+		LeftJoin("class", StaticEq("class.id", "user.class_id"), "*").
+		Where(In("user.id", userIds...)).
+		Query(ctx).AllStruct(&users)
+	assert.EqualError(t, err, "the columns of the joins are not aliased, call AliasJoins to decode them into the nested fields of seal.userWithClass")
+	err = db.Select("age").From("user").
+		LeftJoin("class", StaticEq("class.id", "user.class_id"), "*").
+		Where(In("user.id", userIds...)).
+		Query(ctx).OneStruct(&users[0])
+	assert.EqualError(t, err, "the columns of the joins are not aliased, call AliasJoins to decode them into the nested fields of seal.userWithClass")
+
+	// the columns are not aliased without joins
+	row, err := db.Select("id").From("class").AndSelect("class", "name").AliasJoins().
+		Where(Eq("id", classID)).
+		Query(ctx).OneMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]interface{}{"id": classID, "name": className}, row)
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// insertRe matches the column list of an insert statement
+	insertRe = regexp.MustCompile(`(?is)^\s*(?:INSERT|REPLACE)\s+(?:IGNORE\s+)?INTO\s+\S+\s*\(([^)]*)\)\s*VALUES\s*`)
+	// predicateRe matches the column and operator just before a placeholder,
+	// e.g. "name=", "id IN (?,", "age BETWEEN ? AND "
+	predicateRe = regexp.MustCompile("(?i)([\\w.`\"\\[\\]]+)\\s*(?:=|<>|!=|<=|>=|<|>|\\s(?:NOT\\s+)?LIKE|\\s(?:NOT\\s+)?IN\\s*\\([^()]*|\\s(?:NOT\\s+)?BETWEEN(?:\\s+\\S+\\s+AND)?)\\s*$")
+)
+
+// argColumns guesses the column each placeholder of the sql is bound to,
+// the column is empty if it can not be recognized. The result always has n elements.
+func argColumns(sql string, placeholder string, n int) []string {
+	cols := make([]string, n)
+	positions := placeholders(sql, placeholder, n)
+
+	var insertCols []string
+	valuesAt := -1
+	if m := insertRe.FindStringSubmatchIndex(sql); m != nil {
+		for _, col := range strings.Split(sql[m[2]:m[3]], ",") {
+			insertCols = append(insertCols, columnName(col))
+		}
+		valuesAt = m[1]
+	}
+
+	inValues := 0
+	for i, pos := range positions {
+		if insertCols != nil && pos >= valuesAt && !strings.Contains(strings.ToUpper(sql[valuesAt:pos]), "ON DUPLICATE") {
+			cols[i] = insertCols[inValues%len(insertCols)]
+			inValues++
+			continue
+		}
+		if m := predicateRe.FindStringSubmatch(sql[:pos]); m != nil {
+			cols[i] = columnName(m[1])
+		}
+	}
+	return cols
+}
+
+// placeholders return the offsets of the first n placeholders outside the quoted strings
+func placeholders(sql string, placeholder string, n int) []int {
+	pos := make([]int, 0, n)
+	for i := 0; i < len(sql) && len(pos) < n; i++ {
+		c := sql[i]
+		if c == '\'' || c == '"' || c == '`' {
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+		if strings.HasPrefix(sql[i:], placeholder) {
+			pos = append(pos, i)
+			i += len(placeholder) - 1
+		}
+	}
+	return pos
+}
+
+// columnName strips the spaces, quotes and table of the column
+func columnName(col string) string {
+	col = strings.Trim(strings.TrimSpace(col), "`\"[]")
+	if i := strings.LastIndexByte(col, '.'); i >= 0 {
+		col = strings.Trim(col[i+1:], "`\"[]")
+	}
+	return col
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONHandler writes each entry as a single line json object
+type JSONHandler struct {
+	mu       sync.Mutex
+	w        io.Writer
+	minLevel Level
+}
+
+var _ Handler = &JSONHandler{}
+
+// NewJSONHandler generate a handler which writes the entries not lower than minLevel to w
+func NewJSONHandler(w io.Writer, minLevel Level) *JSONHandler {
+	return &JSONHandler{w: w, minLevel: minLevel}
+}
+
+// Log writes the entry like {"time":"...","level":"INFO","msg":"seal exec","sql":"...",...}
+func (h *JSONHandler) Log(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+	if level < h.minLevel {
+		return
+	}
+	buf := make([]byte, 0, 256)
+	buf = append(buf, `{"time":`...)
+	buf = appendJSON(buf, time.Now().Format(time.RFC3339Nano))
+	buf = append(buf, `,"level":`...)
+	buf = appendJSON(buf, level.String())
+	buf = append(buf, `,"msg":`...)
+	buf = appendJSON(buf, msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		buf = append(buf, ',')
+		buf = appendJSON(buf, fmt.Sprint(keyvals[i]))
+		buf = append(buf, ':')
+		if i+1 < len(keyvals) {
+			buf = appendJSON(buf, keyvals[i+1])
+		} else {
+			buf = append(buf, "null"...)
+		}
+	}
+	buf = append(buf, "}\n"...)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, _ = h.w.Write(buf)
+}
+
+// appendJSON appends the json of the value, errors and values which can not be marshaled are written as string
+func appendJSON(buf []byte, val interface{}) []byte {
+	if err, ok := val.(error); ok {
+		val = err.Error()
+	}
+	b, err := json.Marshal(val)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(val))
+	}
+	return append(buf, b...)
+}
@@ -0,0 +1,271 @@
+// Package logger adapts the build and exec logs of seal to a structured logger.
+// Entries are emitted as a message with key/value pairs like log/slog does,
+// the args can be redacted by column name or predicate and huge args are truncated.
+//
+//	lg := logger.New(logger.NewJSONHandler(os.Stderr, logger.LevelInfo),
+//		logger.WithRedactColumns("password", "token"),
+//		logger.WithReadableSQL(builder.NewMysqlBuilder()))
+//	db, err := seal.Open("mysql", dsn, logger.WithLogger(lg))
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/options"
+)
+
+// Level is the severity of the log entry, the values are the same as log/slog
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+// String return the name of the level
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// keys of the log entry
+const (
+	KeyTraceId     = "trace_id"
+	KeySQL         = "sql"
+	KeyArgs        = "args"
+	KeyDurationMs  = "duration_ms"
+	KeyReadableSQL = "readable_sql"
+	KeyError       = "error"
+)
+
+// messages of the log entry
+const (
+	MsgBuild = "seal build"
+	MsgExec  = "seal exec"
+)
+
+// Redacted replaces the value of the redacted args
+const Redacted = "[REDACTED]"
+
+// Handler writes the log entry, keyvals are the alternating keys and values like log/slog
+type Handler interface {
+	Log(ctx context.Context, level Level, msg string, keyvals ...interface{})
+}
+
+// HandlerFunc adapts a func to Handler
+type HandlerFunc func(ctx context.Context, level Level, msg string, keyvals ...interface{})
+
+// Log calls f
+func (f HandlerFunc) Log(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+	f(ctx, level, msg, keyvals...)
+}
+
+// RedactFunc reports whether the arg should be redacted,
+// column is the name of the column the arg is bound to (without table and quotes), empty if unknown
+type RedactFunc func(column string, val interface{}) bool
+
+// Logger generates the structured entries of the build and exec logs
+type Logger struct {
+	h Handler
+
+	successLevel  Level
+	failureLevel  Level
+	buildLevel    Level
+	slowLevel     Level
+	slowThreshold time.Duration
+
+	redactCols map[string]struct{}
+	redactFn   RedactFunc
+	maxArgLen  int
+	b          builder.Builder
+}
+
+// Option configures the Logger
+type Option func(l *Logger)
+
+// DefaultMaxArgLength is the default max length of the string and bytes args
+const DefaultMaxArgLength = 1024
+
+// New generate a Logger which writes to the handler.
+// By default successful executions are logged at LevelInfo, failed builds and executions at LevelError,
+// successful builds at LevelDebug, and args longer than DefaultMaxArgLength are truncated.
+func New(h Handler, opts ...Option) *Logger {
+	l := &Logger{
+		h:            h,
+		successLevel: LevelInfo,
+		failureLevel: LevelError,
+		buildLevel:   LevelDebug,
+		slowLevel:    LevelWarn,
+		redactCols:   make(map[string]struct{}),
+		maxArgLen:    DefaultMaxArgLength,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// WithLevels set the levels of the successful and failed executions
+func WithLevels(success Level, failure Level) Option {
+	return func(l *Logger) {
+		l.successLevel = success
+		l.failureLevel = failure
+	}
+}
+
+// WithBuildLevel set the level of the successful builds, failed builds are logged at the failure level
+func WithBuildLevel(level Level) Option {
+	return func(l *Logger) {
+		l.buildLevel = level
+	}
+}
+
+// WithSlowLevel set the level of the successful executions which take at least threshold
+func WithSlowLevel(threshold time.Duration, level Level) Option {
+	return func(l *Logger) {
+		l.slowThreshold = threshold
+		l.slowLevel = level
+	}
+}
+
+// WithRedactColumns redacts the args bound to the columns, the names are case insensitive
+func WithRedactColumns(cols ...string) Option {
+	return func(l *Logger) {
+		for _, col := range cols {
+			l.redactCols[strings.ToLower(col)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactFunc redacts the args which the predicate reports true
+func WithRedactFunc(fn RedactFunc) Option {
+	return func(l *Logger) {
+		l.redactFn = fn
+	}
+}
+
+// WithMaxArgLength set the max length of the string and bytes args, truncation is disabled when n is 0
+func WithMaxArgLength(n int) Option {
+	return func(l *Logger) {
+		l.maxArgLen = n
+	}
+}
+
+// WithReadableSQL add the sql interpolated with the (redacted) args in the dialect of the builder.
+// The readable sql is only used for reading, never execute it.
+func WithReadableSQL(b builder.Builder) Option {
+	return func(l *Logger) {
+		l.b = b
+	}
+}
+
+// WithLogger set both the build and exec logger of seal
+func WithLogger(l *Logger) options.SealOptionsFunc {
+	return func(opt *options.SealOptions) {
+		opt.ExecLog = l.ExecLog
+		opt.BuildLog = l.BuildLog
+	}
+}
+
+// ExecLog implements options.ExecLogFunc
+func (l *Logger) ExecLog(ctx context.Context, ts time.Duration, sql string, args []interface{}, err error) {
+	level := l.successLevel
+	if err != nil {
+		level = l.failureLevel
+	} else if l.slowThreshold > 0 && ts >= l.slowThreshold {
+		level = l.slowLevel
+	}
+	l.log(ctx, level, MsgExec, ts, sql, args, err)
+}
+
+// BuildLog implements options.BuildLogFunc
+func (l *Logger) BuildLog(ctx context.Context, ts time.Duration, sql string, args []interface{}, err error) {
+	level := l.buildLevel
+	if err != nil {
+		level = l.failureLevel
+	}
+	l.log(ctx, level, MsgBuild, ts, sql, args, err)
+}
+
+// log generates the key/values of the entry and write it to the handler
+func (l *Logger) log(ctx context.Context, level Level, msg string, ts time.Duration, sql string, args []interface{}, err error) {
+	kvs := make([]interface{}, 0, 12)
+	if traceId := ctx.Value(options.DefaultTraceKey); traceId != nil {
+		kvs = append(kvs, KeyTraceId, traceId)
+	}
+	args = l.sanitize(sql, args)
+	kvs = append(kvs, KeySQL, sql, KeyArgs, args, KeyDurationMs, float64(ts)/float64(time.Millisecond))
+	if l.b != nil && sql != "" {
+		kvs = append(kvs, KeyReadableSQL, builder.Interpolate(l.b, sql, args))
+	}
+	if err != nil {
+		kvs = append(kvs, KeyError, err)
+	}
+	l.h.Log(ctx, level, msg, kvs...)
+}
+
+// sanitize return a copy of args in which the sensitive args are redacted and the huge args are truncated
+func (l *Logger) sanitize(sql string, args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return args
+	}
+	var cols []string
+	if len(l.redactCols) > 0 || l.redactFn != nil {
+		pl := "?"
+		if l.b != nil {
+			pl = l.b.Placeholder()
+		}
+		cols = argColumns(sql, pl, len(args))
+	}
+	out := make([]interface{}, len(args))
+	for i, arg := range args {
+		if cols != nil && l.redact(cols[i], arg) {
+			out[i] = Redacted
+			continue
+		}
+		out[i] = l.truncate(arg)
+	}
+	return out
+}
+
+// redact reports whether the arg bound to the column should be redacted
+func (l *Logger) redact(col string, val interface{}) bool {
+	if _, ok := l.redactCols[strings.ToLower(col)]; ok && col != "" {
+		return true
+	}
+	return l.redactFn != nil && l.redactFn(col, val)
+}
+
+// truncate cuts the string and bytes longer than maxArgLen
+func (l *Logger) truncate(val interface{}) interface{} {
+	if l.maxArgLen <= 0 {
+		return val
+	}
+	switch v := val.(type) {
+	case string:
+		if len(v) > l.maxArgLen {
+			return fmt.Sprintf("%s...(%d bytes truncated)", v[:l.maxArgLen], len(v)-l.maxArgLen)
+		}
+	case []byte:
+		if len(v) > l.maxArgLen {
+			return fmt.Sprintf("%x...(%d bytes truncated)", v[:l.maxArgLen], len(v)-l.maxArgLen)
+		}
+	}
+	return val
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+type entry struct {
+	level Level
+	msg   string
+	kvs   map[string]interface{}
+}
+
+func capture() (Handler, *[]entry) {
+	entries := make([]entry, 0)
+	return HandlerFunc(func(ctx context.Context, level Level, msg string, keyvals ...interface{}) {
+		kvs := make(map[string]interface{})
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			kvs[keyvals[i].(string)] = keyvals[i+1]
+		}
+		entries = append(entries, entry{level, msg, kvs})
+	}), &entries
+}
+
+func TestLoggerLevels(t *testing.T) {
+	ctx := context.WithValue(context.Background(), options.DefaultTraceKey, "t1")
+	h, entries := capture()
+	l := New(h, WithSlowLevel(time.Second, LevelWarn))
+
+	l.BuildLog(ctx, time.Millisecond, "SELECT 1", nil, nil)
+	l.ExecLog(ctx, time.Millisecond, "SELECT 1", nil, nil)
+	l.ExecLog(ctx, 2*time.Second, "SELECT 1", nil, nil)
+	l.ExecLog(ctx, time.Millisecond, "SELECT 1", nil, errors.New("boom"))
+
+	assert.Len(t, *entries, 4)
+	assert.Equal(t, []Level{LevelDebug, LevelInfo, LevelWarn, LevelError},
+		[]Level{(*entries)[0].level, (*entries)[1].level, (*entries)[2].level, (*entries)[3].level})
+	assert.Equal(t, MsgBuild, (*entries)[0].msg)
+	assert.Equal(t, MsgExec, (*entries)[1].msg)
+	assert.Equal(t, "t1", (*entries)[1].kvs[KeyTraceId])
+	assert.Equal(t, 1.0, (*entries)[1].kvs[KeyDurationMs])
+	assert.EqualError(t, (*entries)[3].kvs[KeyError].(error), "boom")
+}
+
+func TestLoggerRedact(t *testing.T) {
+	ctx := context.Background()
+	h, entries := capture()
+	l := New(h,
+		WithRedactColumns("Password"),
+		WithRedactFunc(func(col string, val interface{}) bool {
+			s, ok := val.(string)
+			return ok && strings.HasPrefix(s, "sk_")
+		}),
+		WithMaxArgLength(4),
+		WithReadableSQL(builder.NewMysqlBuilder()))
+
+	l.ExecLog(ctx, 0, "INSERT INTO user (name,password) VALUES (?,?),(?,?)", []interface{}{"ab", "p1", "abcdefg", "p2"}, nil)
+	l.ExecLog(ctx, 0, "UPDATE user SET `password`=?,token=? WHERE u.name=? AND id IN (?,?) AND age BETWEEN ? AND ?",
+		[]interface{}{"p3", "sk_1", "x", 1, 2, 3, 4}, nil)
+
+	assert.Equal(t, []interface{}{"ab", Redacted, "abcd...(3 bytes truncated)", Redacted}, (*entries)[0].kvs[KeyArgs])
+	assert.Equal(t, `INSERT INTO user (name,password) VALUES ('ab','[REDACTED]'),('abcd...(3 bytes truncated)','[REDACTED]')`, (*entries)[0].kvs[KeyReadableSQL])
+	assert.Equal(t, []interface{}{Redacted, Redacted, "x", 1, 2, 3, 4}, (*entries)[1].kvs[KeyArgs])
+}
+
+func TestArgColumns(t *testing.T) {
+	assert.Equal(t, []string{"name", "age", "id", "id", "id", "score", "score", ""},
+		argColumns("SELECT * FROM s WHERE `s`.`name` LIKE ? AND age>=? AND id NOT IN (?, ?, ?) AND note='a=?' AND score BETWEEN ? AND ? LIMIT ?", "?", 8))
+	assert.Equal(t, []string{"name", "age", "age"},
+		argColumns("INSERT INTO s (name, age) VALUES (?,?) ON DUPLICATE KEY UPDATE age=?", "?", 3))
+}
+
+func TestJSONHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(NewJSONHandler(&buf, LevelInfo))
+	l.BuildLog(context.Background(), 0, "SELECT 1", nil, nil)
+	l.ExecLog(context.Background(), 0, "SELECT * FROM s WHERE\nid=?", []interface{}{1}, errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &m); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "ERROR", m["level"])
+	assert.Equal(t, MsgExec, m["msg"])
+	assert.Equal(t, "SELECT * FROM s WHERE\nid=?", m[KeySQL])
+	assert.Equal(t, []interface{}{1.0}, m[KeyArgs])
+	assert.Equal(t, "boom", m[KeyError])
+}
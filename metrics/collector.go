@@ -0,0 +1,214 @@
+// Package metrics keeps the metrics of seal in memory and exposes them in the prometheus text format.
+//
+//	c := metrics.NewCollector()
+//	db, err := seal.Open("mysql", dsn, options.WithMetrics(c, 15*time.Second))
+//	http.Handle("/metrics", c)
+package metrics
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rumis/seal/options"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the duration histograms
+var DefaultBuckets = []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labels of the series
+type labels struct {
+	op      options.Operation
+	table   string
+	outcome string
+}
+
+// histogram counts the observations into cumulative buckets
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Collector implements options.MetricsCollector
+type Collector struct {
+	buckets []float64
+
+	mu    sync.Mutex
+	build map[labels]*histogram
+	exec  map[labels]*histogram
+	rows  map[labels]int64
+	pool  *sql.DBStats
+}
+
+var _ options.MetricsCollector = &Collector{}
+
+// NewCollector generate a collector, DefaultBuckets is used if buckets is empty
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	bs := make([]float64, len(buckets))
+	copy(bs, buckets)
+	sort.Float64s(bs)
+	return &Collector{
+		buckets: bs,
+		build:   make(map[labels]*histogram),
+		exec:    make(map[labels]*histogram),
+		rows:    make(map[labels]int64),
+	}
+}
+
+// ObserveBuild observes the duration of building a statement
+func (c *Collector) ObserveBuild(op options.Operation, table string, outcome string, d time.Duration) {
+	c.observe(c.build, labels{op, table, outcome}, d)
+}
+
+// ObserveExec observes the duration of executing a statement
+func (c *Collector) ObserveExec(op options.Operation, table string, outcome string, d time.Duration) {
+	c.observe(c.exec, labels{op, table, outcome}, d)
+}
+
+// AddRows counts the rows returned by a query or affected by an execution
+func (c *Collector) AddRows(op options.Operation, table string, n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rows[labels{op: op, table: table}] += n
+}
+
+// PoolStats keeps the latest stats of the connection pool
+func (c *Collector) PoolStats(stats sql.DBStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pool = &stats
+}
+
+// observe add the duration to the histogram of the labels
+func (c *Collector) observe(hs map[labels]*histogram, l labels, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, ok := hs[l]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		hs[l] = h
+	}
+	v := d.Seconds()
+	for i, le := range c.buckets {
+		if v <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// ServeHTTP writes the metrics in the prometheus text format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := c.WritePrometheus(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// WritePrometheus writes the metrics in the prometheus text format.
+// Series are sorted by labels so the output is deterministic.
+func (c *Collector) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	c.writeHistograms(bw, "seal_build_duration_seconds", "Duration of building statements.", c.build)
+	c.writeHistograms(bw, "seal_exec_duration_seconds", "Duration of executing statements.", c.exec)
+
+	fmt.Fprintf(bw, "# HELP seal_rows_total Rows returned by queries or affected by executions.\n")
+	fmt.Fprintf(bw, "# TYPE seal_rows_total counter\n")
+	rowLabels := make([]labels, 0, len(c.rows))
+	for l := range c.rows {
+		rowLabels = append(rowLabels, l)
+	}
+	for _, l := range sortLabels(rowLabels) {
+		fmt.Fprintf(bw, "seal_rows_total{operation=%s,table=%s} %d\n", quote(string(l.op)), quote(l.table), c.rows[l])
+	}
+
+	if c.pool != nil {
+		gauges := []struct {
+			name, help string
+			val        string
+		}{
+			{"seal_pool_max_open_connections", "Maximum number of open connections.", strconv.Itoa(c.pool.MaxOpenConnections)},
+			{"seal_pool_open_connections", "Number of established connections.", strconv.Itoa(c.pool.OpenConnections)},
+			{"seal_pool_in_use_connections", "Number of connections currently in use.", strconv.Itoa(c.pool.InUse)},
+			{"seal_pool_idle_connections", "Number of idle connections.", strconv.Itoa(c.pool.Idle)},
+		}
+		for _, g := range gauges {
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", g.name, g.help, g.name, g.name, g.val)
+		}
+		counters := []struct {
+			name, help string
+			val        string
+		}{
+			{"seal_pool_wait_count_total", "Total number of connections waited for.", strconv.FormatInt(c.pool.WaitCount, 10)},
+			{"seal_pool_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", formatFloat(c.pool.WaitDuration.Seconds())},
+			{"seal_pool_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", strconv.FormatInt(c.pool.MaxIdleClosed, 10)},
+			{"seal_pool_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", strconv.FormatInt(c.pool.MaxLifetimeClosed, 10)},
+		}
+		for _, m := range counters {
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s counter\n%s %s\n", m.name, m.help, m.name, m.name, m.val)
+		}
+	}
+	return bw.Flush()
+}
+
+// writeHistograms writes the histograms of a metric
+func (c *Collector) writeHistograms(w io.Writer, name string, help string, hs map[labels]*histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	ls := make([]labels, 0, len(hs))
+	for l := range hs {
+		ls = append(ls, l)
+	}
+	for _, l := range sortLabels(ls) {
+		h := hs[l]
+		lv := fmt.Sprintf("operation=%s,table=%s,outcome=%s", quote(string(l.op)), quote(l.table), quote(l.outcome))
+		for i, le := range c.buckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=%s} %d\n", name, lv, quote(formatFloat(le)), h.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, lv, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, lv, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, lv, h.count)
+	}
+}
+
+// sortLabels sorts the labels in place and return it
+func sortLabels(ls []labels) []labels {
+	sort.Slice(ls, func(i, j int) bool {
+		if ls[i].op != ls[j].op {
+			return ls[i].op < ls[j].op
+		}
+		if ls[i].table != ls[j].table {
+			return ls[i].table < ls[j].table
+		}
+		return ls[i].outcome < ls[j].outcome
+	})
+	return ls
+}
+
+// labelEscaper escapes the label value as the prometheus text format required
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// quote escapes and quotes the label value
+func quote(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+// formatFloat formats the float in the shortest representation
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
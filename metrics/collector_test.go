@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	c := NewCollector(0.01, 0.001)
+
+	c.ObserveBuild(options.OpSelect, "user", options.OutcomeSuccess, 500*time.Microsecond)
+	c.ObserveExec(options.OpSelect, "user", options.Outcome(nil), 5*time.Millisecond)
+	c.ObserveExec(options.OpInsert, `us"er`, options.Outcome(errors.New("failed")), 20*time.Millisecond)
+	c.AddRows(options.OpSelect, "user", 3)
+	c.AddRows(options.OpSelect, "user", 2)
+	c.PoolStats(sql.DBStats{MaxOpenConnections: 10, OpenConnections: 2, InUse: 1, Idle: 1, WaitCount: 4, WaitDuration: 1500 * time.Millisecond})
+
+	var buf bytes.Buffer
+	if err := c.WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `# HELP seal_build_duration_seconds Duration of building statements.
+# TYPE seal_build_duration_seconds histogram
+seal_build_duration_seconds_bucket{operation="select",table="user",outcome="success",le="0.001"} 1
+seal_build_duration_seconds_bucket{operation="select",table="user",outcome="success",le="0.01"} 1
+seal_build_duration_seconds_bucket{operation="select",table="user",outcome="success",le="+Inf"} 1
+seal_build_duration_seconds_sum{operation="select",table="user",outcome="success"} 0.0005
+seal_build_duration_seconds_count{operation="select",table="user",outcome="success"} 1
+# HELP seal_exec_duration_seconds Duration of executing statements.
+# TYPE seal_exec_duration_seconds histogram
+seal_exec_duration_seconds_bucket{operation="insert",table="us\"er",outcome="error",le="0.001"} 0
+seal_exec_duration_seconds_bucket{operation="insert",table="us\"er",outcome="error",le="0.01"} 0
+seal_exec_duration_seconds_bucket{operation="insert",table="us\"er",outcome="error",le="+Inf"} 1
+seal_exec_duration_seconds_sum{operation="insert",table="us\"er",outcome="error"} 0.02
+seal_exec_duration_seconds_count{operation="insert",table="us\"er",outcome="error"} 1
+seal_exec_duration_seconds_bucket{operation="select",table="user",outcome="success",le="0.001"} 0
+seal_exec_duration_seconds_bucket{operation="select",table="user",outcome="success",le="0.01"} 1
+seal_exec_duration_seconds_bucket{operation="select",table="user",outcome="success",le="+Inf"} 1
+seal_exec_duration_seconds_sum{operation="select",table="user",outcome="success"} 0.005
+seal_exec_duration_seconds_count{operation="select",table="user",outcome="success"} 1
+# HELP seal_rows_total Rows returned by queries or affected by executions.
+# TYPE seal_rows_total counter
+seal_rows_total{operation="select",table="user"} 5
+# HELP seal_pool_max_open_connections Maximum number of open connections.
+# TYPE seal_pool_max_open_connections gauge
+seal_pool_max_open_connections 10
+# HELP seal_pool_open_connections Number of established connections.
+# TYPE seal_pool_open_connections gauge
+seal_pool_open_connections 2
+# HELP seal_pool_in_use_connections Number of connections currently in use.
+# TYPE seal_pool_in_use_connections gauge
+seal_pool_in_use_connections 1
+# HELP seal_pool_idle_connections Number of idle connections.
+# TYPE seal_pool_idle_connections gauge
+seal_pool_idle_connections 1
+# HELP seal_pool_wait_count_total Total number of connections waited for.
+# TYPE seal_pool_wait_count_total counter
+seal_pool_wait_count_total 4
+# HELP seal_pool_wait_duration_seconds_total Total time blocked waiting for a new connection.
+# TYPE seal_pool_wait_duration_seconds_total counter
+seal_pool_wait_duration_seconds_total 1.5
+# HELP seal_pool_max_idle_closed_total Total number of connections closed due to SetMaxIdleConns.
+# TYPE seal_pool_max_idle_closed_total counter
+seal_pool_max_idle_closed_total 0
+# HELP seal_pool_max_lifetime_closed_total Total number of connections closed due to SetConnMaxLifetime.
+# TYPE seal_pool_max_lifetime_closed_total counter
+seal_pool_max_lifetime_closed_total 0
+`, buf.String())
+}
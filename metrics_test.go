@@ -0,0 +1,87 @@
+package seal
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordMetrics records the metrics reported by seal
+type recordMetrics struct {
+	mu     sync.Mutex
+	builds []string
+	execs  []string
+	rows   map[options.Operation]int64
+	pools  int
+}
+
+func (m *recordMetrics) ObserveBuild(op options.Operation, table string, outcome string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.builds = append(m.builds, string(op)+":"+table+":"+outcome)
+}
+
+func (m *recordMetrics) ObserveExec(op options.Operation, table string, outcome string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.execs = append(m.execs, string(op)+":"+table+":"+outcome)
+}
+
+func (m *recordMetrics) AddRows(op options.Operation, table string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows[op] += n
+}
+
+func (m *recordMetrics) PoolStats(stats sql.DBStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools++
+}
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &recordMetrics{rows: make(map[options.Operation]int64)}
+	db, err := Open("sqlite3", dbfile, options.WithMetrics(m, time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var id int64
+	if err := db.Insert("class").Values([]Class{{Name: "m1"}, {Name: "m2"}}).Exec(ctx, &id); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.Select("name").From("class").Query(ctx).AllMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, rows, 2)
+	var cnt int64
+	err = db.Update("class").Value(map[string]interface{}{"name": "m3"}).Where(Eq("name", "m1")).Exec(ctx, &cnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Select("name").From("unknown_table").Query(ctx).AllMap()
+	assert.Error(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	db.Close()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Equal(t, []string{"insert:class:success", "select:class:success", "update:class:success", "select:unknown_table:success"}, m.builds)
+	assert.Equal(t, []string{"insert:class:success", "select:class:success", "update:class:success", "select:unknown_table:error"}, m.execs)
+	assert.Equal(t, map[options.Operation]int64{options.OpInsert: 2, options.OpSelect: 2, options.OpUpdate: 1}, m.rows)
+	assert.Greater(t, m.pools, 0)
+}
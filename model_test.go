@@ -0,0 +1,70 @@
+package seal
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type userModel struct {
+	ID    int64  `seal:"id,pk,autoincr"`
+	Name  string `seal:"name"`
+	Age   int    `seal:"age"`
+	Class int    `seal:"class_id"`
+}
+
+func (userModel) TableName() string {
+	return "user"
+}
+
+func TestModel(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// create
+	u := userModel{Name: randString(8), Age: 13, Class: 1}
+	err = db.Model(&u).Create(ctx)
+	assert.Nil(t, err)
+	assert.NotZero(t, u.ID)
+
+	// find
+	var found userModel
+	err = db.Model(&found).Find(ctx, u.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, u, found)
+
+	// save
+	found.Age = 14
+	err = db.Model(&found).Save(ctx)
+	assert.Nil(t, err)
+	var saved userModel
+	assert.Nil(t, db.Model(&saved).Find(ctx, u.ID))
+	assert.Equal(t, 14, saved.Age)
+
+	// save creates the model without id
+	u2 := userModel{Name: randString(8)}
+	assert.Nil(t, db.Model(&u2).Save(ctx))
+	assert.Equal(t, u.ID+1, u2.ID)
+
+	// delete
+	assert.Nil(t, db.Model(&saved).Delete(ctx))
+	err = db.Model(&found).Find(ctx, u.ID)
+	assert.True(t, errors.Is(err, ErrNoRows))
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+
+	// invalid model
+	assert.Error(t, db.Model(u).Create(ctx))
+	assert.Error(t, db.Model(&struct{ Name string }{}).Delete(ctx))
+}
@@ -0,0 +1,45 @@
+package seal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlyOmit(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type user struct {
+		Name  string `seal:"name"`
+		Age   int    `seal:"age,omitempty"`
+		Class int    `seal:"class_id"`
+	}
+	name := randString(8)
+	var id int64
+	err = db.Insert("user").Omit("class_id").Value(user{Name: name, Age: 13, Class: 2}).Exec(ctx, &id)
+	assert.Nil(t, err)
+
+	// set the age back to zero past omitempty
+	var cnt int64
+	err = db.Update("user").Only("age").Value(user{Name: randString(8)}).Where(Eq("id", id)).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+
+	var found userModel
+	assert.Nil(t, db.Model(&found).Find(ctx, id))
+	assert.Equal(t, userModel{ID: id, Name: name, Age: 0, Class: 0}, found)
+
+	err = db.Update("user").Omit("class").Value(user{Name: name}).Where(Eq("id", id)).Exec(ctx, &cnt)
+	assert.EqualError(t, err, "Update.Value: unknown column class")
+}
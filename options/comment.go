@@ -0,0 +1,97 @@
+package options
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// CommentTag is a key/value pair of the sql comment, the value is read from the context by Key
+type CommentTag struct {
+	Name string
+	Key  interface{}
+}
+
+// TraceIdTag reads the trace id from the context by DefaultTraceKey
+var TraceIdTag = CommentTag{Name: "trace_id", Key: DefaultTraceKey}
+
+// SQLCommenter generates the comment appended to each statement
+type SQLCommenter struct {
+	Tags []CommentTag
+	// Caller add the file and line of the code which executes the statement
+	Caller bool
+}
+
+// WithSQLComment append a comment like /* trace_id=..., route=..., caller=file:line */ to each statement.
+// The trace id is always tagged, the other tags are read from the context.
+// Statements with comment are not cached by the statement cache as the comment is different for each of them,
+// and the prepared queries are sent as raw statements so each execution is commented.
+func WithSQLComment(caller bool, tags ...CommentTag) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.SQLComment = &SQLCommenter{
+			Tags:   append([]CommentTag{TraceIdTag}, tags...),
+			Caller: caller,
+		}
+	}
+}
+
+// Comment generates the comment of the statement, empty string is returned if nothing to tag
+func (c *SQLCommenter) Comment(ctx context.Context) string {
+	parts := make([]string, 0, len(c.Tags)+1)
+	for _, tag := range c.Tags {
+		v := ctx.Value(tag.Key)
+		if v == nil {
+			continue
+		}
+		parts = append(parts, escapeComment(tag.Name)+"="+escapeComment(fmt.Sprint(v)))
+	}
+	if c.Caller {
+		if caller := callerOutsideSeal(); caller != "" {
+			parts = append(parts, "caller="+escapeComment(caller))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " /* " + strings.Join(parts, ", ") + " */"
+}
+
+// escapeComment percent-encodes the chars except letters, digits and "-_.:/@~",
+// so the content can never terminate the comment early, eg. "*/", or be taken as a placeholder, eg. "?".
+func escapeComment(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			b.WriteByte(c)
+		case strings.IndexByte("-_.:/@~", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// sealPkgPrefix is the package path prefix of seal
+const sealPkgPrefix = "github.com/rumis/seal"
+
+// callerOutsideSeal return the first caller which is not in the seal packages, as dir/file.go:line
+func callerOutsideSeal() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		inSeal := strings.HasPrefix(frame.Function, sealPkgPrefix+".") || strings.HasPrefix(frame.Function, sealPkgPrefix+"/")
+		if !inSeal || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
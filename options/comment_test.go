@@ -0,0 +1,28 @@
+package options
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type routeKey struct{}
+
+func TestSQLComment(t *testing.T) {
+	ctx := context.WithValue(context.Background(), DefaultTraceKey, "3f2a")
+	ctx = context.WithValue(ctx, routeKey{}, "GET /users/{id}?x=*/ DROP")
+
+	opt := DefaultSealOptions()
+	WithSQLComment(false, CommentTag{Name: "route", Key: routeKey{}})(opt)
+	assert.Equal(t, " /* trace_id=3f2a, route=GET%20/users/%7Bid%7D%3Fx%3D%2A/%20DROP */", opt.SQLComment.Comment(ctx))
+
+	// untagged values are skipped
+	assert.Equal(t, "", opt.SQLComment.Comment(context.Background()))
+
+	WithSQLComment(true)(opt)
+	comment := opt.SQLComment.Comment(ctx)
+	assert.True(t, strings.HasPrefix(comment, " /* trace_id=3f2a, caller=options/comment_test.go:"), comment)
+	assert.NotContains(t, comment[3:len(comment)-3], "*/")
+}
@@ -0,0 +1,64 @@
+package options
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Operation is the kind of a statement
+type Operation string
+
+const (
+	// OpSelect statement built by select query
+	OpSelect Operation = "select"
+	// OpInsert statement built by insert query
+	OpInsert Operation = "insert"
+	// OpUpdate statement built by update query
+	OpUpdate Operation = "update"
+	// OpDelete statement built by delete query
+	OpDelete Operation = "delete"
+	// OpExec raw statement executed by ExecContext
+	OpExec Operation = "exec"
+	// OpQuery raw statement queried by QueryContext
+	OpQuery Operation = "query"
+)
+
+// QueryInfo describes the statement which is going to be executed.
+// Interceptors can rewrite the SQL and Args before calling the next handler.
+type QueryInfo struct {
+	Op    Operation
+	Table string
+	SQL   string
+	Args  []interface{}
+}
+
+// HasRows reports whether the statement returns rows
+func (i *QueryInfo) HasRows() bool {
+	return i.Op == OpSelect || i.Op == OpQuery
+}
+
+// QueryResult is the outcome of a statement.
+// Result is set when the statement is executed, Rows is set when the statement is queried.
+type QueryResult struct {
+	Result sql.Result
+	Rows   *sql.Rows
+}
+
+// QueryHandler executes the statement described by info
+type QueryHandler func(ctx context.Context, info *QueryInfo) (QueryResult, error)
+
+// InterceptorFunc wraps the execution of each statement.
+// It can modify the info before calling next, skip next and return its own result or error,
+// or inspect the result returned by next.
+type InterceptorFunc func(ctx context.Context, info *QueryInfo, next QueryHandler) (QueryResult, error)
+
+// ChainInterceptors wraps the handler with the interceptors, the first interceptor is the outermost one
+func ChainInterceptors(h QueryHandler, ics ...InterceptorFunc) QueryHandler {
+	for i := len(ics) - 1; i >= 0; i-- {
+		ic, next := ics[i], h
+		h = func(ctx context.Context, info *QueryInfo) (QueryResult, error) {
+			return ic(ctx, info, next)
+		}
+	}
+	return h
+}
@@ -0,0 +1,32 @@
+package options
+
+import (
+	"database/sql"
+	"time"
+)
+
+// outcomes of the build or execution
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Outcome return the outcome label of the error
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+// MetricsCollector receives the metrics of the statements and the connection pool
+type MetricsCollector interface {
+	// ObserveBuild observes the duration of building a statement
+	ObserveBuild(op Operation, table string, outcome string, d time.Duration)
+	// ObserveExec observes the duration of executing a statement
+	ObserveExec(op Operation, table string, outcome string, d time.Duration)
+	// AddRows counts the rows returned by a query or affected by an execution
+	AddRows(op Operation, table string, n int64)
+	// PoolStats receives the stats of the connection pool periodically
+	PoolStats(stats sql.DBStats)
+}
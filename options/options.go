@@ -1,10 +1,51 @@
 package options
 
+import "time"
+
 // SealOptions the global options
 type SealOptions struct {
 	EncodeHook EncodeHookFunc
 	ExecLog    ExecLogFunc
 	BuildLog   BuildLogFunc
+
+	// Driver is the name of the driver which the errors are translated by, the dialect of the builder is used if it is empty
+	Driver string
+
+	// StmtCacheSize is the max count of the cached prepared statements, cache is disabled when it is 0
+	StmtCacheSize int
+
+	// Interceptors wrap the execution of each statement, the first one is the outermost
+	Interceptors []InterceptorFunc
+
+	// Tracer starts a span for each build and execution of statement
+	Tracer Tracer
+
+	// Metrics receives the metrics of the statements and the connection pool
+	Metrics MetricsCollector
+	// PoolStatsInterval is the interval of reporting the stats of the connection pool, disabled when it is 0
+	PoolStatsInterval time.Duration
+
+	// SlowQueryLog is called for the statements which take longer than SlowQueryThreshold
+	SlowQueryLog       SlowQueryFunc
+	SlowQueryThreshold time.Duration
+	// SlowQueryExplain enable attaching the execution plan to the slow query log
+	SlowQueryExplain bool
+	// SlowQuerySampleRate is the rate of the slow statements which will be logged, all are logged when it is 0
+	SlowQuerySampleRate float64
+
+	// SQLComment generates the comment appended to each statement
+	SQLComment *SQLCommenter
+
+	// Timestamps are the created and updated time columns of the tables
+	Timestamps map[string]TableTimestamps
+	// Clock return the current time of the created and updated time, time.Now is used if it is nil
+	Clock ClockFunc
+
+	// SoftDeletes are the deleted time columns of the soft deleted tables
+	SoftDeletes map[string]string
+
+	// MaxRows rejects the SELECT which has no LIMIT or has a LIMIT greater than it, disabled when it is 0
+	MaxRows int64
 }
 
 // SealOptionsFunc SealOptions
@@ -37,3 +78,47 @@ func WithBuildLogger(blog BuildLogFunc) SealOptionsFunc {
 		opt.BuildLog = blog
 	}
 }
+
+// WithDriver set the name of the driver which the errors are translated by, see sqlerr.Register.
+// Open sets it to its driver name, it should be set for OpenWithDB and OpenWithExecutor if the builder is not of the driver.
+func WithDriver(name string) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.Driver = name
+	}
+}
+
+// WithStmtCache enable the prepared statement cache, at most size statements are cached
+func WithStmtCache(size int) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.StmtCacheSize = size
+	}
+}
+
+// WithInterceptor append an interceptor to the chain
+func WithInterceptor(ic InterceptorFunc) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.Interceptors = append(opt.Interceptors, ic)
+	}
+}
+
+// WithTracer set the tracer
+func WithTracer(t Tracer) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.Tracer = t
+	}
+}
+
+// WithMetrics set the metrics collector, the stats of the connection pool are reported every poolInterval
+func WithMetrics(c MetricsCollector, poolInterval time.Duration) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.Metrics = c
+		opt.PoolStatsInterval = poolInterval
+	}
+}
+
+// WithMaxRows rejects the SELECT which may return more than n rows unless All is called on the query
+func WithMaxRows(n int64) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.MaxRows = n
+	}
+}
@@ -0,0 +1,48 @@
+package options
+
+import (
+	"context"
+	"time"
+)
+
+// SlowQuery is the log entry of a statement which takes longer than the threshold
+type SlowQuery struct {
+	Op       Operation
+	Table    string
+	SQL      string
+	Args     []interface{}
+	Duration time.Duration
+	Err      error
+
+	// Plan is the result of EXPLAIN, it is set only when the explain is enabled
+	Plan []map[string]interface{}
+	// ExplainErr is the error of running EXPLAIN
+	ExplainErr error
+}
+
+// SlowQueryFunc is called each time when a slow statement is found.
+// It is called in a separate goroutine if the explain is enabled.
+type SlowQueryFunc func(ctx context.Context, q SlowQuery)
+
+// WithSlowQueryLog set the sink of the statements which take longer than the threshold
+func WithSlowQueryLog(threshold time.Duration, sink SlowQueryFunc) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.SlowQueryThreshold = threshold
+		opt.SlowQueryLog = sink
+	}
+}
+
+// WithSlowQueryExplain enable re-running the slow statements as EXPLAIN on a separate connection,
+// the execution plan is attached to the log entry.
+func WithSlowQueryExplain() SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.SlowQueryExplain = true
+	}
+}
+
+// WithSlowQuerySampling set the rate in (0, 1] of the slow statements which will be logged
+func WithSlowQuerySampling(rate float64) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.SlowQuerySampleRate = rate
+	}
+}
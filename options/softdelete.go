@@ -0,0 +1,13 @@
+package options
+
+// WithSoftDelete enables the soft delete of the table, a row is deleted when the column is not NULL.
+// The DELETE of the table sets the column to the current time, and the SELECT and UPDATE exclude the deleted rows.
+// The models can also declare the column by the softdelete tag.
+func WithSoftDelete(table string, column string) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		if opt.SoftDeletes == nil {
+			opt.SoftDeletes = make(map[string]string)
+		}
+		opt.SoftDeletes[table] = column
+	}
+}
@@ -0,0 +1,39 @@
+package options
+
+import "time"
+
+// TableTimestamps are the columns of a table which are filled with the current time.
+// Create is filled on insert if it is not set, Update is filled on insert if it is not set and on every update.
+type TableTimestamps struct {
+	Create string
+	Update string
+}
+
+// ClockFunc return the current time
+type ClockFunc func() time.Time
+
+// WithTimestamps set the created and updated time columns of the table, empty column is ignored.
+// The columns can also be declared by the autocreatetime and autoupdatetime tags of the struct values.
+func WithTimestamps(table string, createCol string, updateCol string) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		if opt.Timestamps == nil {
+			opt.Timestamps = make(map[string]TableTimestamps)
+		}
+		opt.Timestamps[table] = TableTimestamps{Create: createCol, Update: updateCol}
+	}
+}
+
+// WithClock set the clock of the created and updated time, time.Now is used by default
+func WithClock(clock ClockFunc) SealOptionsFunc {
+	return func(opt *SealOptions) {
+		opt.Clock = clock
+	}
+}
+
+// Now return the current time of the clock
+func (opt *SealOptions) Now() time.Time {
+	if opt.Clock != nil {
+		return opt.Clock()
+	}
+	return time.Now()
+}
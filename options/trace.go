@@ -0,0 +1,137 @@
+package options
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// keys of the span attributes
+const (
+	AttrDialect      = "db.system"
+	AttrOperation    = "db.operation"
+	AttrTable        = "db.sql.table"
+	AttrStatement    = "db.statement"
+	AttrRowsAffected = "db.rows_affected"
+)
+
+// names of the spans
+const (
+	SpanBuild = "seal.build"
+	SpanExec  = "seal.exec"
+)
+
+// Attribute is a key/value pair attached to a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attr generate an attribute
+func Attr(key string, value interface{}) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span represents an operation tracked by the Tracer
+type Span interface {
+	// SetAttributes sets the attributes of the span
+	SetAttributes(attrs ...Attribute)
+	// RecordError records the error as the outcome of the span
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+// Tracer starts a span for each build and execution of statement
+type Tracer interface {
+	// Start creates a span which is the child of the span in ctx (if any),
+	// the returned context contains the new span.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// RecordedSpan is the span recorded by RecordingTracer
+type RecordedSpan struct {
+	ID         int
+	ParentID   int
+	Name       string
+	Attributes map[string]interface{}
+	Err        error
+	StartTime  time.Time
+	EndTime    time.Time
+
+	mu *sync.Mutex
+}
+
+// SetAttributes sets the attributes of the span
+func (s *RecordedSpan) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		s.Attributes[attr.Key] = attr.Value
+	}
+}
+
+// RecordError records the error as the outcome of the span
+func (s *RecordedSpan) RecordError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Err = err
+}
+
+// End completes the span
+func (s *RecordedSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.EndTime = time.Now()
+}
+
+// recordedSpanKey is the context key of the current RecordedSpan
+type recordedSpanKey struct{}
+
+// RecordingTracer keeps all the spans in memory, it is used for tests
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewRecordingTracer generate a RecordingTracer
+func NewRecordingTracer() *RecordingTracer {
+	return &RecordingTracer{}
+}
+
+// Start creates a span which is the child of the span in ctx
+func (t *RecordingTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &RecordedSpan{
+		ID:         len(t.spans) + 1,
+		Name:       name,
+		Attributes: make(map[string]interface{}),
+		StartTime:  time.Now(),
+		mu:         &t.mu,
+	}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*RecordedSpan); ok {
+		span.ParentID = parent.ID
+	}
+	for _, attr := range attrs {
+		span.Attributes[attr.Key] = attr.Value
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+// Spans return the spans in the order of started
+func (t *RecordingTracer) Spans() []*RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]*RecordedSpan, len(t.spans))
+	copy(spans, t.spans)
+	return spans
+}
+
+// Reset drop all the recorded spans
+func (t *RecordingTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
@@ -0,0 +1,79 @@
+// Package otelseal adapts an OpenTelemetry tracer to the seal tracer.
+//
+//	tracer := otel.Tracer("github.com/rumis/seal")
+//	db, err := seal.Open("mysql", dsn, options.WithTracer(otelseal.NewTracer(tracer)))
+package otelseal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rumis/seal/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracer starts the spans by an OpenTelemetry tracer
+type Tracer struct {
+	t trace.Tracer
+}
+
+var _ options.Tracer = Tracer{}
+
+// NewTracer generate a seal tracer with an OpenTelemetry tracer
+func NewTracer(t trace.Tracer) Tracer {
+	return Tracer{t: t}
+}
+
+// Start creates a client span which is the child of the span in ctx
+func (t Tracer) Start(ctx context.Context, name string, attrs ...options.Attribute) (context.Context, options.Span) {
+	ctx, span := t.t.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(KeyValues(attrs...)...),
+	)
+	return ctx, Span{s: span}
+}
+
+// Span wraps an OpenTelemetry span
+type Span struct {
+	s trace.Span
+}
+
+// SetAttributes sets the attributes of the span
+func (s Span) SetAttributes(attrs ...options.Attribute) {
+	s.s.SetAttributes(KeyValues(attrs...)...)
+}
+
+// RecordError records the error and marks the status of the span as error
+func (s Span) RecordError(err error) {
+	s.s.RecordError(err)
+	s.s.SetStatus(codes.Error, err.Error())
+}
+
+// End completes the span
+func (s Span) End() {
+	s.s.End()
+}
+
+// KeyValues converts the seal attributes to OpenTelemetry attributes
+func KeyValues(attrs ...options.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(attr.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(attr.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(attr.Key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(attr.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(attr.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
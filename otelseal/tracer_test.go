@@ -0,0 +1,70 @@
+package otelseal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fakeSpan struct {
+	trace.Span
+	name   string
+	kind   trace.SpanKind
+	attrs  []attribute.KeyValue
+	status codes.Code
+	ended  bool
+}
+
+func (s *fakeSpan) SetAttributes(kv ...attribute.KeyValue)        { s.attrs = append(s.attrs, kv...) }
+func (s *fakeSpan) RecordError(err error, _ ...trace.EventOption) {}
+func (s *fakeSpan) SetStatus(code codes.Code, _ string)           { s.status = code }
+func (s *fakeSpan) End(_ ...trace.SpanEndOption)                  { s.ended = true }
+
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	span := &fakeSpan{
+		Span:  trace.SpanFromContext(ctx),
+		name:  name,
+		kind:  cfg.SpanKind(),
+		attrs: cfg.Attributes(),
+	}
+	t.spans = append(t.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func TestTracer(t *testing.T) {
+	ft := &fakeTracer{}
+	tracer := NewTracer(ft)
+
+	_, span := tracer.Start(context.Background(), options.SpanExec,
+		options.Attr(options.AttrOperation, "select"),
+		options.Attr(options.AttrRowsAffected, int64(3)),
+	)
+	span.SetAttributes(options.Attr("custom", []int{1}))
+	span.RecordError(errors.New("failed"))
+	span.End()
+
+	if !assert.Len(t, ft.spans, 1) {
+		t.FailNow()
+	}
+	s := ft.spans[0]
+	assert.Equal(t, options.SpanExec, s.name)
+	assert.Equal(t, trace.SpanKindClient, s.kind)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String(options.AttrOperation, "select"),
+		attribute.Int64(options.AttrRowsAffected, 3),
+		attribute.String("custom", "[1]"),
+	}, s.attrs)
+	assert.Equal(t, codes.Error, s.status)
+	assert.True(t, s.ended)
+}
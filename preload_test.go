@@ -0,0 +1,176 @@
+package seal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/rumis/seal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+type preloadClass struct {
+	ID    int64         `seal:"id"`
+	Name  string        `seal:"name"`
+	Users []preloadUser `seal:"users,has_many=class_id"`
+}
+
+func (preloadClass) TableName() string {
+	return "class"
+}
+
+type preloadUser struct {
+	ID      int64         `seal:"id"`
+	Name    string        `seal:"name"`
+	ClassID int64         `seal:"class_id"`
+	Class   *preloadClass `seal:"class,belongs_to=class_id"`
+}
+
+func (preloadUser) TableName() string {
+	return "user"
+}
+
+type preloadMonitorClass struct {
+	ID      int64        `seal:"id"`
+	Name    string       `seal:"name"`
+	Monitor *preloadUser `seal:"monitor,has_one=class_id"`
+}
+
+func (preloadMonitorClass) TableName() string {
+	return "class"
+}
+
+func TestPreload(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var classIds []int64
+	for _, name := range []string{"A", "B"} {
+		var id int64
+		if err := db.Insert("class").Value(Class{Name: name}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+		classIds = append(classIds, id)
+	}
+	// the relation fields are not inserted
+	users := []preloadUser{
+		{Name: "u1", ClassID: classIds[0]},
+		{Name: "u2", ClassID: classIds[0], Class: &preloadClass{Name: "ignored"}},
+		{Name: "u3", ClassID: classIds[1]},
+	}
+	for _, u := range users {
+		var id int64
+		if err := db.Insert("user").Omit("id").Value(u).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// belongs to with nested has many
+	var found []preloadUser
+	err = db.Select("id", "name", "class_id").From("user").
+		Where(In("name", "u1", "u3")).OrderBy("id").
+		Preload("Class").
+		Preload("Class.Users", func(q *query.SelectQuery) {
+			q.OrderBy("id DESC")
+		}).
+		Query(ctx).AllStruct(&found)
+	assert.Nil(t, err)
+	assert.Len(t, found, 2)
+	assert.Equal(t, "A", found[0].Class.Name)
+	assert.Equal(t, "B", found[1].Class.Name)
+	assert.Equal(t, []string{"u2", "u1"}, []string{found[0].Class.Users[0].Name, found[0].Class.Users[1].Name})
+	assert.Len(t, found[1].Class.Users, 1)
+
+	// has many with filter
+	var class preloadClass
+	err = db.Select("id", "name").From("class").Where(Eq("id", classIds[0])).
+		Preload("Users", func(q *query.SelectQuery) {
+			q.Where(Eq("name", "u2"))
+		}).
+		Query(ctx).OneStruct(&class)
+	assert.Nil(t, err)
+	assert.Len(t, class.Users, 1)
+	assert.Equal(t, "u2", class.Users[0].Name)
+
+	// has one
+	var monitors []preloadMonitorClass
+	err = db.Select("id", "name").From("class").Where(In("id", classIds[1], classIds[1]+1)).
+		Preload("Monitor").
+		Query(ctx).AllStruct(&monitors)
+	assert.Nil(t, err)
+	assert.Len(t, monitors, 1)
+	assert.Equal(t, "u3", monitors[0].Monitor.Name)
+
+	var id int64
+	err = db.Insert("class").Value(Class{Name: "C"}).Exec(ctx, &id)
+	assert.Nil(t, err)
+	monitors = nil
+	err = db.Select("id", "name").From("class").OrderBy("id").
+		Preload("Monitor", func(q *query.SelectQuery) {
+			q.Where(Eq("name", "u3"))
+		}).
+		Query(ctx).AllStruct(&monitors)
+	assert.Nil(t, err)
+	assert.Len(t, monitors, 3)
+	assert.Nil(t, monitors[0].Monitor)
+	assert.Equal(t, "u3", monitors[1].Monitor.Name)
+	assert.Nil(t, monitors[2].Monitor)
+
+	// the key column of the relation is not selected
+	err = db.Select("id", "name").From("user").Preload("Class").Query(ctx).AllStruct(&[]preloadUser{})
+	assert.EqualError(t, err, "preload: the column class_id of seal.preloadUser is not selected for the relation Class")
+	err = db.Select("name").From("class").Preload("Users").Query(ctx).OneStruct(&preloadClass{})
+	assert.EqualError(t, err, "preload: the column id of seal.preloadClass is not selected for the relation Users")
+
+	err = db.Select("id").From("class").Preload("Teacher").All().Query(ctx).AllStruct(&[]preloadClass{})
+	assert.EqualError(t, err, "preload: seal.preloadClass has no relation Teacher")
+}
+
+func TestPreloadBatch(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// more classes than the keys of one IN query
+	classes := make([]map[string]interface{}, 0, 1200)
+	users := make([]map[string]interface{}, 0, 1200)
+	for i := 1; i <= 1200; i++ {
+		classes = append(classes, map[string]interface{}{"id": i, "name": fmt.Sprintf("c%d", i)})
+		users = append(users, map[string]interface{}{"name": fmt.Sprintf("uc%d", i), "class_id": i})
+	}
+	var id int64
+	err = db.Insert("class").Values(classes).Exec(ctx, &id)
+	assert.Nil(t, err)
+	err = db.Insert("user").Values(users).Exec(ctx, &id)
+	assert.Nil(t, err)
+
+	var found []preloadUser
+	err = db.Select("id", "name", "class_id").From("user").OrderBy("id").All().
+		Preload("Class").
+		Query(ctx).AllStruct(&found)
+	assert.Nil(t, err)
+	assert.Len(t, found, len(classes))
+	for _, u := range found {
+		if assert.NotNil(t, u.Class) {
+			assert.Equal(t, u.ClassID, u.Class.ID)
+			assert.Equal(t, "u"+u.Class.Name, u.Name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package seal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreparedQuery(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	names := []string{randString(8), randString(9), randString(10)}
+	for _, name := range names {
+		var id int64
+		if err := db.Insert("class").Value(Class{Name: name}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pq, err := db.Select("id", "name").
+		From("class").
+		Where(Eq("name", Param("name"))).
+		Or(Between("id", Param("from"), Param("to"))).
+		OrderBy("id ASC").
+		Prepare(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pq.Close()
+	assert.Equal(t, "SELECT id,name FROM class WHERE name=? OR id BETWEEN ? AND ? ORDER BY id ASC", pq.SQL())
+
+	type classRow struct {
+		ID   int    `seal:"id"`
+		Name string `seal:"name"`
+	}
+
+	// bind with map
+	classes := make([]classRow, 0)
+	err = pq.Bind(map[string]interface{}{"name": names[0], "from": 0, "to": 0}).Query(ctx).AllStruct(&classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, classes, 1)
+	assert.Equal(t, names[0], classes[0].Name)
+
+	// bind with struct
+	classes = make([]classRow, 0)
+	err = pq.Bind(struct {
+		Name string `seal:"name"`
+		From int    `seal:"from"`
+		To   int    `seal:"to"`
+	}{Name: names[0], From: 2, To: 3}).Query(ctx).AllStruct(&classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{names[0], names[1], names[2]}, []string{classes[0].Name, classes[1].Name, classes[2].Name})
+
+	// the zero fields of struct are bound even if they are omitempty
+	classes = make([]classRow, 0)
+	err = pq.Bind(struct {
+		Name string `seal:"name,omitempty"`
+		From int    `seal:"from,omitempty"`
+		To   int    `seal:"to,omitempty"`
+	}{Name: names[1]}).Query(ctx).AllStruct(&classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, classes, 1)
+	assert.Equal(t, names[1], classes[0].Name)
+
+	// missing param
+	_, err = pq.Bind(map[string]interface{}{"name": names[0]}).Query(ctx).AllMap()
+	assert.EqualError(t, err, "named parameter not bound: from")
+
+	// a param can not be expanded by a slice
+	pin, err := db.Select("id").From("class").Where(In("name", Param("names"))).Prepare(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pin.Close()
+	_, err = pin.Bind(map[string]interface{}{"names": names}).Query(ctx).AllMap()
+	assert.EqualError(t, err, "named parameter can not be bound to a slice: names")
+
+	// exec
+	pd, err := db.Delete("class").Where(In("name", Param("n1"), Param("n2"))).Prepare(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pd.Close()
+	cnt, err := pd.Bind(map[string]interface{}{"n1": names[1], "n2": names[2]}).Exec(ctx).RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(2), cnt)
+}
@@ -0,0 +1,37 @@
+package query
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/schema"
+)
+
+// UpdateChanged updates only the columns whose fields are changed from before to after, including the fields set to zero.
+// before and after must be the structs or pointers to structs of the same type, before is usually a copy of after taken when it is loaded.
+// The copy must not share the pointer, slice and map fields with after, otherwise the changes made through them are missed,
+// schema.Snapshot makes such a deep copy.
+// No statement is executed if nothing is changed. If the struct has a version field, the update is versioned by the version of before.
+// The BeforeUpdate hook of after is called before the diff. It return the count of the affected rows.
+func (q Query) UpdateChanged(ctx context.Context, table string, before, after interface{}, where expr.Expr) (int64, error) {
+	after, err := schema.BeforeUpdate(ctx, after)
+	if err != nil {
+		return 0, err
+	}
+	changed, err := schema.Changes(before, after)
+	if err != nil {
+		return 0, err
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+	uq := q.Update(table).Value(changed).Where(where)
+	s, _ := schema.Parse(after)
+	if vf := s.Version; vf != nil {
+		uq.Version(vf.Column, vf.Interface(reflect.Indirect(reflect.ValueOf(before))))
+	}
+	var cnt int64
+	err = uq.Exec(ctx, &cnt)
+	return cnt, err
+}
@@ -0,0 +1,126 @@
+package query
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/internal/fakedriver"
+)
+
+// Statement is a statement captured by the DryRunExecutor
+type Statement struct {
+	SQL  string
+	Args []interface{}
+}
+
+// DryRunResultFunc generates the fake result of the executed statement
+type DryRunResultFunc func(stmt Statement) (lastInsertId int64, rowsAffected int64)
+
+// DryRunExecutor records the statements instead of sending them to the database.
+// It is backed by an in-memory driver, so it can also begin transactions and prepare statements,
+// which are recorded as BEGIN, COMMIT and ROLLBACK. Queries return no rows.
+type DryRunExecutor struct {
+	*sql.DB
+
+	b builder.Builder
+
+	mu     sync.Mutex
+	stmts  []Statement
+	nextId int64
+	result DryRunResultFunc
+}
+
+// NewDryRunExecutor generate a dry-run executor, the builder is used to interpolate the script.
+// By default each execution returns an increasing last insert id and 1 affected row.
+func NewDryRunExecutor(b builder.Builder) *DryRunExecutor {
+	e := &DryRunExecutor{b: b}
+	e.result = func(stmt Statement) (int64, int64) {
+		e.nextId++
+		return e.nextId, 1
+	}
+	e.DB = fakedriver.Open(dryRunHandler{e: e})
+	return e
+}
+
+// SetResult set the fake result of all the executions
+func (e *DryRunExecutor) SetResult(lastInsertId int64, rowsAffected int64) {
+	e.SetResultFunc(func(stmt Statement) (int64, int64) {
+		return lastInsertId, rowsAffected
+	})
+}
+
+// SetResultFunc set the func which generates the fake result of each execution
+func (e *DryRunExecutor) SetResultFunc(fn DryRunResultFunc) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.result = fn
+}
+
+// Statements return the captured statements in the order of execution
+func (e *DryRunExecutor) Statements() []Statement {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stmts := make([]Statement, len(e.stmts))
+	copy(stmts, e.stmts)
+	return stmts
+}
+
+// Reset drop the captured statements
+func (e *DryRunExecutor) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stmts = nil
+}
+
+// Script return the captured statements with the args interpolated as literals of the dialect, one statement per line.
+// The script is for reviewing, the literals may not be exactly the same as the driver sends.
+func (e *DryRunExecutor) Script() string {
+	var s strings.Builder
+	for _, stmt := range e.Statements() {
+		s.WriteString(builder.Interpolate(e.b, stmt.SQL, stmt.Args))
+		s.WriteString(";\n")
+	}
+	return s.String()
+}
+
+// record captures the statement and return its fake result
+func (e *DryRunExecutor) record(query string, args []driver.NamedValue) driver.Result {
+	stmt := Statement{SQL: query, Args: fakedriver.Args(args)}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stmts = append(e.stmts, stmt)
+	return fakedriver.NewResult(e.result(stmt))
+}
+
+// dryRunHandler records all the statements of the connections to the executor
+type dryRunHandler struct {
+	e *DryRunExecutor
+}
+
+func (h dryRunHandler) Begin() error {
+	h.e.record("BEGIN", nil)
+	return nil
+}
+
+func (h dryRunHandler) Commit() error {
+	h.e.record("COMMIT", nil)
+	return nil
+}
+
+func (h dryRunHandler) Rollback() error {
+	h.e.record("ROLLBACK", nil)
+	return nil
+}
+
+func (h dryRunHandler) Exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	return h.e.record(query, args), nil
+}
+
+// Query records the statement and return an empty result set
+func (h dryRunHandler) Query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	h.e.record(query, args)
+	return fakedriver.NewRows(nil, nil), nil
+}
@@ -11,4 +11,12 @@ type Executor interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 	// QueryContext queries a SQL statement with the given context
 	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	// PrepareContext creates a prepared statement with the given context
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// stmtBinder is implemented by the executors which are bound to a transaction, eg. *sql.Tx
+type stmtBinder interface {
+	// StmtContext returns a transaction-specific prepared statement from an existing statement.
+	StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt
 }
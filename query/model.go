@@ -0,0 +1,340 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/schema"
+	"github.com/rumis/seal/utils"
+)
+
+// ModelQuery runs the CRUD of a struct model by its primary key
+type ModelQuery struct {
+	baseQ  Query
+	model  interface{}
+	rv     reflect.Value
+	schema *schema.Schema
+	err    error
+
+	// snapshot is the copy of the model when it is found or saved, Save only updates the changed fields if it is valid
+	snapshot reflect.Value
+}
+
+// Model generate the query of the model, model must be a pointer to struct
+func (q Query) Model(model interface{}) *ModelQuery {
+	m := &ModelQuery{baseQ: q, model: model}
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		m.err = fmt.Errorf("model must be a non-nil pointer to struct, got %T", model)
+		return m
+	}
+	m.rv = rv.Elem()
+	m.schema, m.err = schema.Parse(model)
+	return m
+}
+
+// Schema return the schema of the model
+func (m *ModelQuery) Schema() *schema.Schema {
+	return m.schema
+}
+
+// Find queries the row by the primary key and fills the model, the AfterFind hook is called.
+// An error matching ErrNoRows and sql.ErrNoRows is returned if the row is not found.
+func (m *ModelQuery) Find(ctx context.Context, id interface{}) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	sq := m.baseQ.Select(m.schema.Columns()...).
+		From(m.schema.Table).
+		Where(expr.Op(m.schema.PK.Column, "=", id)).
+		Limit(1)
+	sq.bs.SoftDelete(m.softDelete())
+	row, err := sq.Query(ctx).OneMap()
+	if err != nil {
+		return err
+	}
+	if len(row) == 0 {
+		return m.baseQ.translate(sql.ErrNoRows)
+	}
+	if err := utils.Map2Struct(row, m.model); err != nil {
+		return err
+	}
+	if err := schema.AfterFind(ctx, m.model); err != nil {
+		return err
+	}
+	m.Snapshot()
+	return nil
+}
+
+// Create inserts the model after calling the BeforeInsert hook, the auto increment primary key is filled back if it is zero.
+// The created and updated time fields are set to the current time before inserting.
+func (m *ModelQuery) Create(ctx context.Context) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	if _, err := schema.BeforeInsert(ctx, m.model); err != nil {
+		return err
+	}
+	pk := m.schema.PK
+	autoId := pk.AutoIncr && pk.IsZero(m.rv)
+	touched := m.touch(true)
+	vals, err := utils.Struct2Map(m.model)
+	if err != nil {
+		return err
+	}
+	m.schema.StripRelations(vals)
+	if err := m.stamp(vals, touched); err != nil {
+		return err
+	}
+	if autoId {
+		delete(vals, pk.Column)
+	}
+	if sf := m.schema.SoftDelete; sf != nil && sf.IsZero(m.rv) {
+		delete(vals, sf.Column)
+	}
+	var id int64
+	if err := m.baseQ.Insert(m.schema.Table).Value(vals).Exec(ctx, &id); err != nil {
+		return err
+	}
+	if autoId {
+		if err := setInt(pk.Value(m.rv), id); err != nil {
+			return err
+		}
+	}
+	m.Snapshot()
+	return nil
+}
+
+// Save updates all the columns of the model by the primary key after calling the BeforeUpdate hook,
+// the model is created if the primary key is zero.
+// The updated time fields are set to the current time, the created time fields are not updated if they are zero.
+// If the model has a version field, ErrStaleObject is returned when the row is changed by others,
+// otherwise the version field is incremented.
+// If a snapshot is taken by the same ModelQuery, only the changed fields are updated, and nothing is executed if none is changed.
+func (m *ModelQuery) Save(ctx context.Context) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	pk := m.schema.PK
+	pkVal := pk.Value(m.rv)
+	if pk.IsZero(m.rv) {
+		return m.Create(ctx)
+	}
+	if _, err := schema.BeforeUpdate(ctx, m.model); err != nil {
+		return err
+	}
+	var changed map[string]interface{}
+	if m.snapshot.IsValid() {
+		if changed = m.schema.Diff(m.snapshot, m.rv); len(changed) == 0 {
+			return nil
+		}
+	}
+	touched := m.touch(false)
+	vals, err := utils.Struct2Map(m.model)
+	if err != nil {
+		return err
+	}
+	m.schema.StripRelations(vals)
+	if changed != nil {
+		// the changed fields are encoded as the full struct, the zero fields dropped by omitempty are kept
+		for col := range changed {
+			if v, ok := vals[col]; ok {
+				changed[col] = v
+			}
+		}
+		vals = changed
+	}
+	if err := m.stamp(vals, touched); err != nil {
+		return err
+	}
+	delete(vals, pk.Column)
+	for _, f := range m.schema.Fields {
+		if m.isCreateTime(f) && f.IsZero(m.rv) {
+			delete(vals, f.Column)
+		}
+	}
+	if sf := m.schema.SoftDelete; sf != nil {
+		delete(vals, sf.Column)
+	}
+	if len(vals) == 0 {
+		return nil
+	}
+	uq := m.baseQ.Update(m.schema.Table).Value(vals).Where(expr.Op(pk.Column, "=", pkVal.Interface()))
+	uq.bu.SoftDelete(m.softDelete())
+	vf := m.schema.Version
+	if vf != nil {
+		uq.Version(vf.Column, vf.Interface(m.rv))
+	}
+	var cnt int64
+	if err := uq.Exec(ctx, &cnt); err != nil {
+		return err
+	}
+	if vf != nil {
+		if err := incr(vf.Value(m.rv)); err != nil {
+			return err
+		}
+	}
+	m.Snapshot()
+	return nil
+}
+
+// Snapshot keeps a deep copy of the model, the following Save only updates the fields changed since then,
+// including the fields set to zero. The snapshot is also taken by Find, Create and Save.
+func (m *ModelQuery) Snapshot() *ModelQuery {
+	if m.err == nil {
+		m.snapshot = schema.Snapshot(m.rv)
+	}
+	return m
+}
+
+// Changes return the columns changed since the snapshot with the current values, nil if there is no snapshot
+func (m *ModelQuery) Changes() map[string]interface{} {
+	if m.err != nil || !m.snapshot.IsValid() {
+		return nil
+	}
+	return m.schema.Diff(m.snapshot, m.rv)
+}
+
+// Delete deletes the row of the model by the primary key.
+// The row is soft deleted if the model has a softdelete tag or the table is registered by options.WithSoftDelete,
+// and the deleted time field of the model is set if it is a time.Time or *time.Time.
+func (m *ModelQuery) Delete(ctx context.Context) error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	pk := m.schema.PK
+	pkVal := pk.Value(m.rv)
+	if pk.IsZero(m.rv) {
+		return errors.New("model delete: primary key " + pk.Column + " is zero")
+	}
+	sd := m.softDelete()
+	dq := m.baseQ.Delete(m.schema.Table).Where(expr.Op(pk.Column, "=", pkVal.Interface()))
+	dq.bd.SoftDelete(sd)
+	dq.softCol = sd.Columns[m.schema.Table]
+	var cnt int64
+	if err := dq.Exec(ctx, &cnt); err != nil {
+		return err
+	}
+	if sf := m.schema.SoftDelete; sf != nil && dq.isSoft() {
+		setTime(sf.Value(m.rv), m.baseQ.opts.Now())
+	}
+	return nil
+}
+
+// softDelete return the soft delete of the model, the column tagged with softdelete overrides the table option
+func (m *ModelQuery) softDelete() builder.SoftDelete {
+	sd := m.baseQ.softDelete(false)
+	if m.schema.SoftDelete == nil {
+		return sd
+	}
+	cols := make(map[string]string, len(sd.Columns)+1)
+	for table, col := range sd.Columns {
+		cols[table] = col
+	}
+	cols[m.schema.Table] = m.schema.SoftDelete.Column
+	sd.Columns = cols
+	return sd
+}
+
+// check return the error of the model
+func (m *ModelQuery) check() error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.schema.PK == nil {
+		return fmt.Errorf("%w: %s", schema.ErrNoPrimaryKey, m.schema.Type)
+	}
+	return nil
+}
+
+// touch sets the updated time fields of the model to the current time,
+// and the zero created time fields if create is true. Only the fields of time.Time are set.
+// The columns of the set fields are returned.
+func (m *ModelQuery) touch(create bool) []string {
+	ts := m.baseQ.opts.Timestamps[m.schema.Table]
+	now := reflect.ValueOf(m.baseQ.opts.Now())
+	var cols []string
+	for _, f := range m.schema.Fields {
+		fv := f.Value(m.rv)
+		if f.Type != now.Type() || !fv.CanSet() {
+			continue
+		}
+		if f.AutoUpdateTime || f.Column == ts.Update || (create && m.isCreateTime(f) && fv.IsZero()) {
+			fv.Set(now)
+			cols = append(cols, f.Column)
+		}
+	}
+	return cols
+}
+
+// stamp sets the columns set by touch to the current time encoded by the EncodeHook,
+// so the model writes the same value as the time columns filled by the builder
+func (m *ModelQuery) stamp(vals map[string]interface{}, cols []string) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	now, err := m.baseQ.now()
+	if err != nil {
+		return err
+	}
+	for _, col := range cols {
+		vals[col] = now
+	}
+	return nil
+}
+
+// isCreateTime reports whether the field is a created time column
+func (m *ModelQuery) isCreateTime(f *schema.Field) bool {
+	return f.AutoCreateTime || f.Column == m.baseQ.opts.Timestamps[m.schema.Table].Create
+}
+
+// setTime sets the time to the field of time.Time or *time.Time, the other types are ignored
+func setTime(v reflect.Value, t time.Time) {
+	if !v.IsValid() {
+		return
+	}
+	switch v.Interface().(type) {
+	case time.Time:
+		v.Set(reflect.ValueOf(t))
+	case *time.Time:
+		v.Set(reflect.ValueOf(&t))
+	}
+}
+
+// incr increments the integer field
+func incr(v reflect.Value) error {
+	if !v.IsValid() {
+		return errors.New("version field is in a nil embedded struct")
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(v.Uint() + 1)
+	default:
+		return fmt.Errorf("version field %s must be an integer", v.Type())
+	}
+	return nil
+}
+
+// setInt sets the id to the integer field
+func setInt(v reflect.Value, id int64) error {
+	if !v.IsValid() {
+		return errors.New("auto increment field is in a nil embedded struct")
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(id))
+	default:
+		return fmt.Errorf("auto increment field %s must be an integer", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/schema"
+)
+
+// preloadBatchSize is the max number of keys in the IN query of a relation, the keys are queried in batches to keep under the variable limit of the database
+const preloadBatchSize = 500
+
+// preload is a relation loaded after the structs are decoded
+type preload struct {
+	// name is the struct field of the relation
+	name   string
+	scopes []func(q *SelectQuery)
+	// nested are the relations of the related models
+	nested []*preload
+}
+
+// Preload loads the relation of the struct field name after AllStruct or OneStruct decodes the structs,
+// the related models are queried by batched IN queries per relation, so the key column of the relation must be selected.
+// Nested relations are separated by dot, e.g. "Class.Teacher", the scopes of the path filter the models of the last relation.
+func (s *SelectQuery) Preload(path string, scopes ...func(q *SelectQuery)) *SelectQuery {
+	s.preloads = addPreload(s.preloads, strings.Split(path, "."), scopes)
+	return s
+}
+
+// addPreload adds the preload of the path to the preloads
+func addPreload(preloads []*preload, names []string, scopes []func(q *SelectQuery)) []*preload {
+	var p *preload
+	for _, pl := range preloads {
+		if pl.name == names[0] {
+			p = pl
+			break
+		}
+	}
+	if p == nil {
+		p = &preload{name: names[0]}
+		preloads = append(preloads, p)
+	}
+	if len(names) == 1 {
+		p.scopes = append(p.scopes, scopes...)
+	} else {
+		p.nested = addPreload(p.nested, names[1:], scopes)
+	}
+	return preloads
+}
+
+// loadRelations loads the preloads into ref, ref is a pointer to struct or a pointer to a slice of struct or pointer to struct.
+// cols are the columns of the decoded rows.
+func (q Query) loadRelations(ctx context.Context, ref interface{}, preloads []*preload, cols []string) error {
+	if len(preloads) == 0 {
+		return nil
+	}
+	models := structValues(reflect.ValueOf(ref))
+	if len(models) == 0 {
+		return nil
+	}
+	s, err := schema.ParseType(models[0].Type())
+	if err != nil {
+		return err
+	}
+	for _, p := range preloads {
+		rel := s.Relation(p.name)
+		if rel == nil {
+			return fmt.Errorf("preload: %s has no relation %s", s.Type, p.name)
+		}
+		if err := q.loadRelation(ctx, s, rel, models, p, cols); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadRelation queries the related models of the relation and assigns them to the models
+func (q Query) loadRelation(ctx context.Context, s *schema.Schema, rel *schema.Relation, models []reflect.Value, p *preload, cols []string) error {
+	rs, err := schema.ParseType(rel.Type)
+	if err != nil {
+		return err
+	}
+	// the column of the models and the related models which are matched
+	var key *schema.Field
+	var relKey string
+	if rel.Kind == schema.BelongsTo {
+		key = s.Field(rel.ForeignKey)
+		if key == nil {
+			return fmt.Errorf("preload: %s has no foreign key %s", s.Type, rel.ForeignKey)
+		}
+		if rs.PK == nil {
+			return fmt.Errorf("preload: %w: %s", schema.ErrNoPrimaryKey, rs.Type)
+		}
+		relKey = rs.PK.Column
+	} else {
+		key, relKey = s.PK, rel.ForeignKey
+		if key == nil {
+			return fmt.Errorf("preload: %w: %s", schema.ErrNoPrimaryKey, s.Type)
+		}
+		if rs.Field(relKey) == nil {
+			return fmt.Errorf("preload: %s has no foreign key %s", rs.Type, relKey)
+		}
+	}
+	if !hasColumn(cols, key.Column) {
+		return fmt.Errorf("preload: the column %s of %s is not selected for the relation %s", key.Column, s.Type, p.name)
+	}
+
+	keys := make([]interface{}, 0, len(models))
+	seen := make(map[string]bool, len(models))
+	for _, m := range models {
+		k, ok := keyOf(key.Value(m))
+		if !ok || seen[keyString(k)] {
+			continue
+		}
+		seen[keyString(k)] = true
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	related := reflect.MakeSlice(reflect.SliceOf(rel.Type), 0, len(keys))
+	for start := 0; start < len(keys); start += preloadBatchSize {
+		end := start + preloadBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		sq := q.Select(rs.Columns()...).From(rs.Table).Where(expr.In(relKey, keys[start:end]...)).All()
+		for _, scope := range p.scopes {
+			scope(sq)
+		}
+		sq.preloads = p.nested
+		batch := reflect.New(reflect.SliceOf(rel.Type))
+		if err := sq.Query(ctx).AllStruct(batch.Interface()); err != nil {
+			return err
+		}
+		related = reflect.AppendSlice(related, batch.Elem())
+	}
+
+	// group the related models by the key
+	groups := make(map[string][]reflect.Value)
+	relField := rs.Field(relKey)
+	for i := 0; i < related.Len(); i++ {
+		r := related.Index(i)
+		k, ok := keyOf(relField.Value(r))
+		if !ok {
+			continue
+		}
+		groups[keyString(k)] = append(groups[keyString(k)], r)
+	}
+	for _, m := range models {
+		k, ok := keyOf(key.Value(m))
+		if !ok {
+			continue
+		}
+		assignRelated(rel.Field.Value(m), groups[keyString(k)])
+	}
+	return nil
+}
+
+// assignRelated sets the related models to the field
+func assignRelated(fv reflect.Value, related []reflect.Value) {
+	if fv.Kind() == reflect.Slice {
+		s := reflect.MakeSlice(fv.Type(), 0, len(related))
+		for _, r := range related {
+			s = reflect.Append(s, asType(r, fv.Type().Elem()))
+		}
+		fv.Set(s)
+		return
+	}
+	if len(related) == 0 {
+		fv.Set(reflect.Zero(fv.Type()))
+		return
+	}
+	fv.Set(asType(related[0], fv.Type()))
+}
+
+// asType return the struct r or the pointer to it as t required
+func asType(r reflect.Value, t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.Ptr {
+		return r.Addr()
+	}
+	return r
+}
+
+// structValues return the addressable structs of the pointer to struct or the pointer to a slice of struct or pointer to struct
+func structValues(rv reflect.Value) []reflect.Value {
+	rv = reflect.Indirect(rv)
+	if rv.Kind() == reflect.Struct {
+		return []reflect.Value{rv}
+	}
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	vs := make([]reflect.Value, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		el := rv.Index(i)
+		if el.Kind() == reflect.Ptr {
+			if el.IsNil() {
+				continue
+			}
+			el = el.Elem()
+		}
+		vs = append(vs, el)
+	}
+	return vs
+}
+
+// keyOf return the value of the key field, ok is false if it is zero or a nil pointer
+func keyOf(v reflect.Value) (interface{}, bool) {
+	if !v.IsValid() {
+		return nil, false
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// hasColumn reports whether col is one of cols
+func hasColumn(cols []string, col string) bool {
+	for _, c := range cols {
+		if c == col {
+			return true
+		}
+	}
+	return false
+}
+
+// keyString formats the key to match the keys of different integer types
+func keyString(k interface{}) string {
+	return fmt.Sprint(k)
+}
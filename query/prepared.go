@@ -0,0 +1,139 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/schema"
+)
+
+// PreparedQuery represents a built and prepared statement.
+// It can be executed many times with different values of the named params, the builder is skipped.
+type PreparedQuery struct {
+	baseQ Query
+	stmt  *sql.Stmt
+	info  options.QueryInfo
+}
+
+// SQL return the sql of the prepared statement
+func (p *PreparedQuery) SQL() string {
+	return p.info.SQL
+}
+
+// Bind binds the values of the named params
+// type of val can be map[string]interface{} or struct, the keys of struct are the columns of its seal tags.
+// A param is bound to a single value, so a param in In can not be expanded by a slice.
+func (p *PreparedQuery) Bind(val interface{}) BoundQuery {
+	values, err := bindValues(val)
+	if err != nil {
+		return BoundQuery{err: err}
+	}
+	args := make([]interface{}, len(p.info.Args))
+	for i, arg := range p.info.Args {
+		np, ok := arg.(expr.NamedParam)
+		if !ok {
+			args[i] = arg
+			continue
+		}
+		v, ok := values[np.Name]
+		if !ok {
+			return BoundQuery{err: errors.New("named parameter not bound: " + np.Name)}
+		}
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			return BoundQuery{err: errors.New("named parameter can not be bound to a slice: " + np.Name)}
+		}
+		args[i] = v
+	}
+	return BoundQuery{p: p, args: args}
+}
+
+// bindValues return the values of the named params in the map or struct, all the fields of the struct are kept including the zero ones
+func bindValues(val interface{}) (map[string]interface{}, error) {
+	if values, ok := val.(map[string]interface{}); ok || val == nil {
+		return values, nil
+	}
+	v := reflect.Indirect(reflect.ValueOf(val))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot bind %T, it must be a map or struct", val)
+	}
+	s, err := schema.ParseType(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{}, len(s.Fields))
+	for _, f := range s.Fields {
+		if fv := f.Value(v); fv.IsValid() {
+			values[f.Column] = fv.Interface()
+		}
+	}
+	return values, nil
+}
+
+// Close closes the prepared statement
+func (p *PreparedQuery) Close() error {
+	return p.stmt.Close()
+}
+
+// invoke generate the handler which executes or queries the prepared statement and reports the exec log.
+// The statement is executed as a raw sql if the sql is rewritten by the interceptors,
+// or if the sql comment is enabled, as the comment is generated from the context of each execution.
+func (p *PreparedQuery) invoke(hasRows bool) options.QueryHandler {
+	return func(ctx context.Context, info *options.QueryInfo) (options.QueryResult, error) {
+		if info.SQL != p.info.SQL || p.baseQ.opts.SQLComment != nil {
+			return p.baseQ.invokeAs(ctx, info, hasRows)
+		}
+		sTime := time.Now()
+
+		var res options.QueryResult
+		var err error
+		if hasRows {
+			res.Rows, err = p.stmt.QueryContext(ctx, info.Args...)
+		} else {
+			res.Result, err = p.stmt.ExecContext(ctx, info.Args...)
+		}
+		err = p.baseQ.translate(err)
+
+		p.baseQ.reportExec(ctx, info, time.Since(sTime), err)
+
+		return res, err
+	}
+}
+
+// BoundQuery represents a prepared statement with all the params bound
+type BoundQuery struct {
+	p    *PreparedQuery
+	args []interface{}
+	err  error
+}
+
+// Exec executes the prepared statement
+func (b BoundQuery) Exec(ctx context.Context) sql.Result {
+	if b.err != nil {
+		return NewExecResult(nil, b.err)
+	}
+	return b.p.baseQ.execStmt(ctx, b.info(options.OpExec), b.p.invoke(false))
+}
+
+// Query queries the prepared statement
+func (b BoundQuery) Query(ctx context.Context) Rows {
+	if b.err != nil {
+		return NewRows(nil, b.err)
+	}
+	return b.p.baseQ.queryStmt(ctx, b.info(options.OpQuery), b.p.invoke(true))
+}
+
+// info generate the info of the statement, op is used when the statement is prepared from raw sql
+func (b BoundQuery) info(op options.Operation) *options.QueryInfo {
+	info := b.p.info
+	info.Args = b.args
+	if info.Op == "" {
+		info.Op = op
+	}
+	return &info
+}
@@ -3,23 +3,41 @@ package query
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"reflect"
 	"time"
 
 	"github.com/rumis/seal/builder"
 	"github.com/rumis/seal/expr"
 	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/sqlerr"
 )
 
 // Query represents the sql builder and exector. it is the parent class of db and tx
 type Query struct {
-	b    builder.Builder
-	e    Executor
-	opts *options.SealOptions
+	b     builder.Builder
+	e     Executor
+	opts  *options.SealOptions
+	stmts *StmtCache
+	// root is the executor which the query is created with, it is not changed by WithExecutor
+	root Executor
 }
 
 // NewQuery generate a base query instance
+// the statement cache is created on the executor if it is enabled in the options
 func NewQuery(b builder.Builder, e Executor, opts *options.SealOptions) Query {
-	return Query{b, e, opts}
+	q := Query{b: b, e: e, opts: opts, root: e}
+	if opts.StmtCacheSize > 0 {
+		q.stmts = NewStmtCache(e, opts.StmtCacheSize)
+	}
+	return q
+}
+
+// WithExecutor return a copy of the query which runs on the given executor, eg. a transaction.
+// The copy shares the builder, options and statement cache with the query.
+func (q Query) WithExecutor(e Executor) Query {
+	q.e = e
+	return q
 }
 
 // Builder return the builder
@@ -32,6 +50,19 @@ func (q Query) Options() *options.SealOptions {
 	return q.opts
 }
 
+// Driver return the name of the driver which the errors are translated by, it is the dialect of the builder if it is not set in the options
+func (q Query) Driver() string {
+	if q.opts.Driver != "" {
+		return q.opts.Driver
+	}
+	return q.b.Dialect()
+}
+
+// StmtCache return the prepared statement cache, nil if it is disabled
+func (q Query) StmtCache() *StmtCache {
+	return q.stmts
+}
+
 // Insert generate the insert query
 func (q Query) Insert(table string) *InsertQuery {
 	return NewInsertQuery(q.b, q).Into(table)
@@ -66,28 +97,236 @@ func (q Query) Count(col string) *SelectQuery {
 
 // ExecContext exec a raw sql with context
 func (q Query) ExecContext(ctx context.Context, sql string, args ...interface{}) sql.Result {
+	return q.execStmt(ctx, &options.QueryInfo{Op: options.OpExec, SQL: sql, Args: args}, q.invoke)
+}
+
+// QueryContext exec a raw query sql with context
+func (q Query) QueryContext(ctx context.Context, sql string, args ...interface{}) Rows {
+	return q.queryStmt(ctx, &options.QueryInfo{Op: options.OpQuery, SQL: sql, Args: args}, q.invoke)
+}
+
+// PrepareContext prepare a raw sql with context
+// args can contains named params which are bound when the prepared query is executed
+func (q Query) PrepareContext(ctx context.Context, sql string, args ...interface{}) (*PreparedQuery, error) {
+	return q.prepare(ctx, &options.QueryInfo{SQL: sql, Args: args})
+}
+
+// prepare prepares the statement on the executor
+func (q Query) prepare(ctx context.Context, info *options.QueryInfo) (*PreparedQuery, error) {
+	stmt, err := q.e.PrepareContext(ctx, info.SQL)
+	if err != nil {
+		return nil, q.translate(err)
+	}
+	return &PreparedQuery{baseQ: q, stmt: stmt, info: *info}, nil
+}
+
+// execStmt executes the statement through the interceptors, h is called at the end of the chain
+func (q Query) execStmt(ctx context.Context, info *options.QueryInfo, h options.QueryHandler) sql.Result {
+	res, err := q.run(ctx, info, h)
+	if err == nil && res.Result == nil {
+		err = errors.New("no result returned by the interceptor")
+	}
+	return NewExecResult(res.Result, err)
+}
+
+// queryStmt queries the statement through the interceptors, h is called at the end of the chain
+func (q Query) queryStmt(ctx context.Context, info *options.QueryInfo, h options.QueryHandler) Rows {
+	res, err := q.run(ctx, info, h)
+	if err == nil && res.Rows == nil {
+		err = errors.New("no rows returned by the interceptor")
+	}
+	if err != nil {
+		return NewRows(nil, err)
+	}
+	rows := NewRows(res.Rows, err)
+	rows.ctx = ctx
+	if q.opts.Metrics != nil {
+		m, op, table := q.opts.Metrics, info.Op, info.Table
+		rows.stat = &rowsStat{done: func(n int64) {
+			m.AddRows(op, table, n)
+		}}
+	}
+	return rows
+}
+
+// run passes the statement through the interceptors, h is called at the end of the chain
+func (q Query) run(ctx context.Context, info *options.QueryInfo, h options.QueryHandler) (options.QueryResult, error) {
+	if len(q.opts.Interceptors) > 0 {
+		h = options.ChainInterceptors(h, q.opts.Interceptors...)
+	}
+	if q.opts.Tracer == nil && q.opts.Metrics == nil {
+		return h(ctx, info)
+	}
+
+	var span options.Span
+	if q.opts.Tracer != nil {
+		ctx, span = q.opts.Tracer.Start(ctx, options.SpanExec, q.spanAttrs(info)...)
+		defer span.End()
+	}
 	sTime := time.Now()
-	result, err := q.e.ExecContext(ctx, sql, args...)
 
-	if q.opts.ExecLog != nil {
-		q.opts.ExecLog(ctx, time.Since(sTime), sql, args, err)
+	res, err := h(ctx, info)
+
+	if q.opts.Metrics != nil {
+		q.opts.Metrics.ObserveExec(info.Op, info.Table, options.Outcome(err), time.Since(sTime))
 	}
+	if err != nil {
+		if span != nil {
+			span.RecordError(err)
+		}
+		return res, err
+	}
+	if res.Result != nil {
+		if cnt, err := res.Result.RowsAffected(); err == nil {
+			if span != nil {
+				span.SetAttributes(options.Attr(options.AttrRowsAffected, cnt))
+			}
+			if q.opts.Metrics != nil {
+				q.opts.Metrics.AddRows(info.Op, info.Table, cnt)
+			}
+		}
+	}
+	return res, err
+}
 
-	return NewExecResult(result, err)
+// spanAttrs generate the span attributes of the statement
+func (q Query) spanAttrs(info *options.QueryInfo) []options.Attribute {
+	attrs := []options.Attribute{
+		options.Attr(options.AttrDialect, q.b.Dialect()),
+		options.Attr(options.AttrOperation, string(info.Op)),
+	}
+	if info.Table != "" {
+		attrs = append(attrs, options.Attr(options.AttrTable, info.Table))
+	}
+	if info.SQL != "" {
+		attrs = append(attrs, options.Attr(options.AttrStatement, info.SQL))
+	}
+	return attrs
 }
 
-// QueryContext exec a raw query sql with context
-func (q Query) QueryContext(ctx context.Context, sql string, args ...interface{}) Rows {
+// invoke executes or queries the statement on the executor and reports the exec log
+func (q Query) invoke(ctx context.Context, info *options.QueryInfo) (options.QueryResult, error) {
+	return q.invokeAs(ctx, info, info.HasRows())
+}
+
+// invokeAs executes the statement if hasRows is false, or queries it, regardless of the operation of info
+func (q Query) invokeAs(ctx context.Context, info *options.QueryInfo, hasRows bool) (options.QueryResult, error) {
 	sTime := time.Now()
 
-	rows, err := q.e.QueryContext(ctx, sql, args...)
+	var res options.QueryResult
+	var err error
+	stmt := info.SQL
+	if q.opts.SQLComment != nil {
+		stmt += q.opts.SQLComment.Comment(ctx)
+	}
+	if hasRows {
+		res.Rows, err = q.query(ctx, stmt, info.Args...)
+	} else {
+		res.Result, err = q.exec(ctx, stmt, info.Args...)
+	}
+	err = q.translate(err)
+
+	q.reportExec(ctx, info, time.Since(sTime), err)
+
+	return res, err
+}
 
+// reportExec reports the exec log and slow query log of the statement
+func (q Query) reportExec(ctx context.Context, info *options.QueryInfo, ts time.Duration, err error) {
 	if q.opts.ExecLog != nil {
-		q.opts.ExecLog(ctx, time.Since(sTime), sql, args, err)
+		q.opts.ExecLog(ctx, ts, info.SQL, info.Args, err)
+	}
+	if q.opts.SlowQueryLog != nil && ts > q.opts.SlowQueryThreshold {
+		q.logSlowQuery(ctx, info, ts, err)
+	}
+}
+
+// translate normalizes the error reported by the driver
+func (q Query) translate(err error) error {
+	return sqlerr.Translate(q.Driver(), err)
+}
+
+// now return the current time of the clock encoded by the EncodeHook, it fills the created and updated time columns
+func (q Query) now() (interface{}, error) {
+	t := q.opts.Now()
+	if q.opts.EncodeHook == nil {
+		return t, nil
+	}
+	return q.opts.EncodeHook(reflect.TypeOf(t), t)
+}
+
+// softDelete return the soft delete of the tables registered in options,
+// only selects the deleted rows instead of excluding them
+func (q Query) softDelete(only bool) builder.SoftDelete {
+	return builder.SoftDelete{Columns: q.opts.SoftDeletes, Only: only}
+}
+
+// build builds the sql and args of the statement by fn and reports the build log
+func (q Query) build(ctx context.Context, info *options.QueryInfo, fn func() (string, []interface{}, error)) error {
+	var span options.Span
+	if q.opts.Tracer != nil {
+		ctx, span = q.opts.Tracer.Start(ctx, options.SpanBuild, q.spanAttrs(info)...)
+		defer span.End()
 	}
+	sTime := time.Now()
 
+	sql, args, err := fn()
+
+	ts := time.Since(sTime)
+	if q.opts.BuildLog != nil {
+		q.opts.BuildLog(ctx, ts, sql, args, err)
+	}
+	if q.opts.Metrics != nil {
+		q.opts.Metrics.ObserveBuild(info.Op, info.Table, options.Outcome(err), ts)
+	}
 	if err != nil {
-		return NewRows(nil, err)
+		if span != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+	info.SQL = sql
+	info.Args = args
+	return nil
+}
+
+// exec executes the sql on the executor, the cached statement is used if the statement cache is enabled
+func (q Query) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if q.stmts == nil || q.opts.SQLComment != nil {
+		return q.e.ExecContext(ctx, query, args...)
+	}
+	stmt, release, err := q.stmts.Get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	stmt, closeFn := q.bindStmt(ctx, stmt)
+	defer closeFn()
+	return stmt.ExecContext(ctx, args...)
+}
+
+// query queries the sql on the executor, the cached statement is used if the statement cache is enabled
+func (q Query) query(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if q.stmts == nil || q.opts.SQLComment != nil {
+		return q.e.QueryContext(ctx, query, args...)
+	}
+	stmt, release, err := q.stmts.Get(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	// the statement can be released once the rows are returned, it is closed after rows closed
+	defer release()
+	stmt, closeFn := q.bindStmt(ctx, stmt)
+	defer closeFn()
+	return stmt.QueryContext(ctx, args...)
+}
+
+// bindStmt rebind the statement to the transaction if the executor is a transaction
+func (q Query) bindStmt(ctx context.Context, stmt *sql.Stmt) (*sql.Stmt, func()) {
+	binder, ok := q.e.(stmtBinder)
+	if !ok {
+		return stmt, func() {}
 	}
-	return NewRows(rows, err)
+	txStmt := binder.StmtContext(ctx, stmt)
+	return txStmt, func() { _ = txStmt.Close() }
 }
@@ -2,30 +2,38 @@ package query
 
 import (
 	"context"
-	"time"
 
 	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/options"
 
 	"github.com/rumis/seal/expr"
 )
 
-// DeleteQuery represents the sql builder of delete and base query
+// DeleteQuery represents the sql builder of delete and base query.
+// The DELETE of a soft deleted table is executed as an UPDATE which sets the deleted time column to the current time.
 type DeleteQuery struct {
 	bd    *builder.Delete
 	baseQ Query
+
+	// softCol is the deleted time column of the table, the rows are deleted physically if it is empty
+	softCol string
+	hard    bool
+	// only is set by OnlyDeleted
+	only bool
 }
 
 // NewDeleteQuery constructure of DeleteQuery
 func NewDeleteQuery(b builder.Builder, q Query) *DeleteQuery {
 	return &DeleteQuery{
-		bd:    builder.NewDelete(b),
+		bd:    builder.NewDelete(b).SoftDelete(q.softDelete(false)),
 		baseQ: q,
 	}
 }
 
 // From set table name
 func (d *DeleteQuery) From(table string) *DeleteQuery {
-	d.bd.Table(table)
+	d.bd.Table(table).AutoTime(d.baseQ.now, d.baseQ.opts.Timestamps[table])
+	d.softCol = d.baseQ.opts.SoftDeletes[table]
 	return d
 }
 
@@ -47,23 +55,85 @@ func (d *DeleteQuery) Or(e expr.Expr) *DeleteQuery {
 	return d
 }
 
-// Exec executes a SQL statement
-func (u *DeleteQuery) Exec(ctx context.Context, cnt *int64) error {
-	sTime := time.Now()
+// All allows the statement to delete all the rows when the WHERE clause is not set or always true
+func (d *DeleteQuery) All() *DeleteQuery {
+	d.bd.All()
+	return d
+}
 
-	sql, args, err := u.bd.ToSql()
+// WithDeleted deletes the soft deleted rows too if the rows are deleted by HardDelete,
+// the soft deleted rows are never deleted again by a soft delete, so their deleted time is kept.
+func (d *DeleteQuery) WithDeleted() *DeleteQuery {
+	d.bd.SoftDelete(builder.SoftDelete{})
+	d.only = false
+	return d
+}
 
-	if u.baseQ.opts.BuildLog != nil {
-		u.baseQ.opts.BuildLog(ctx, time.Since(sTime), sql, args, err)
-	}
+// OnlyDeleted deletes only the soft deleted rows, it is usually used with HardDelete to purge the deleted rows.
+// Without HardDelete, the deleted time of the soft deleted rows is updated.
+func (d *DeleteQuery) OnlyDeleted() *DeleteQuery {
+	d.bd.SoftDelete(d.baseQ.softDelete(true))
+	d.only = true
+	return d
+}
 
-	if err != nil {
+// HardDelete deletes the rows physically even if the table is soft deleted
+func (d *DeleteQuery) HardDelete() *DeleteQuery {
+	d.hard = true
+	return d
+}
+
+// Exec executes a SQL statement
+func (u *DeleteQuery) Exec(ctx context.Context, cnt *int64) error {
+	info := u.info()
+	if err := u.baseQ.build(ctx, info, u.toSql); err != nil {
 		return err
 	}
-	result := u.baseQ.ExecContext(ctx, sql, args...)
+	result := u.baseQ.execStmt(ctx, info, u.baseQ.invoke)
+	var err error
 	*cnt, err = result.RowsAffected()
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// Prepare builds and prepares the statement, the returned query can be executed many times with different named params
+func (u *DeleteQuery) Prepare(ctx context.Context) (*PreparedQuery, error) {
+	info := u.info()
+	if err := u.baseQ.build(ctx, info, u.toSql); err != nil {
+		return nil, err
+	}
+	return u.baseQ.prepare(ctx, info)
+}
+
+// isSoft reports whether the rows are soft deleted
+func (u *DeleteQuery) isSoft() bool {
+	return u.softCol != "" && !u.hard
+}
+
+// toSql builds the DELETE, or the UPDATE which sets the deleted time and the updated time if the rows are soft deleted.
+// The soft deleted rows are excluded from the UPDATE unless OnlyDeleted is set.
+func (u *DeleteQuery) toSql() (string, []interface{}, error) {
+	sql, args, err := u.bd.ToSql()
+	if err != nil || !u.isSoft() {
+		return sql, args, err
+	}
+	now, err := u.baseQ.now()
+	if err != nil {
+		return "", nil, err
+	}
+	bu := u.bd.ToUpdate(map[string]interface{}{u.softCol: now})
+	if !u.only {
+		bu.SoftDelete(builder.SoftDelete{Columns: map[string]string{u.bd.TableName(): u.softCol}})
+	}
+	return bu.ToSql()
+}
+
+// info generate the description of the statement, the operation of the soft delete is OpUpdate
+func (u *DeleteQuery) info() *options.QueryInfo {
+	if u.isSoft() {
+		return &options.QueryInfo{Op: options.OpUpdate, Table: u.bd.TableName()}
+	}
+	return &options.QueryInfo{Op: options.OpDelete, Table: u.bd.TableName()}
+}
@@ -2,15 +2,25 @@ package query
 
 import (
 	"context"
-	"time"
 
 	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/schema"
 )
 
 // InsertQuery represents the sql builder of insert and base query
 type InsertQuery struct {
 	bi    *builder.Insert
 	baseQ Query
+
+	// pending are the values set by Value and Values, they are passed to the builder after the BeforeInsert hooks are called
+	pending []pendingValue
+}
+
+// pendingValue is a value of Value or Values
+type pendingValue struct {
+	val  interface{}
+	many bool
 }
 
 // NewInsertQuery constructure of insertquery
@@ -23,45 +33,89 @@ func NewInsertQuery(b builder.Builder, q Query) *InsertQuery {
 
 // Into set the table
 func (i *InsertQuery) Into(table string) *InsertQuery {
-	i.bi.Into(table)
+	i.bi.Into(table).AutoTime(i.baseQ.now, i.baseQ.opts.Timestamps[table])
 	return i
 }
 
-// Columns set columns name when the data type is []interface{} or [][]interface{}
+// Columns set columns name when the data type is []interface{} or [][]interface{}, the maps and structs are inserted by the columns too
 func (i *InsertQuery) Columns(columns ...string) *InsertQuery {
 	i.bi.Columns(columns...)
 	return i
 }
 
-// Values set the data will insert into the table
-func (i *InsertQuery) Values(vals interface{}) *InsertQuery {
-	i.bi.Values(vals)
+// Only inserts only the columns of the values, including the zero fields dropped by omitempty.
+// An unknown column is returned as an error by Exec.
+func (i *InsertQuery) Only(cols ...string) *InsertQuery {
+	i.bi.Only(cols...)
 	return i
 }
 
-// Value set the data will insert into the table
-func (i *InsertQuery) Value(val interface{}) *InsertQuery {
-	i.bi.Value(val)
+// Omit drops the columns from the values, an unknown column is returned as an error by Exec
+func (i *InsertQuery) Omit(cols ...string) *InsertQuery {
+	i.bi.Omit(cols...)
 	return i
 }
 
-// Exec executes a SQL statement
-func (u *InsertQuery) Exec(ctx context.Context, lastId *int64) error {
-	sTime := time.Now()
+// Values set the data will insert into the table.
+// The BeforeInsert hook of each struct is called when the statement is built, and the error aborts the statement.
+func (i *InsertQuery) Values(vals interface{}) *InsertQuery {
+	i.pending = append(i.pending, pendingValue{val: vals, many: true})
+	return i
+}
 
-	sql, args, err := u.bi.ToSql()
+// Value set the data will insert into the table.
+// The BeforeInsert hook of the struct is called when the statement is built, and the error aborts the statement.
+func (i *InsertQuery) Value(val interface{}) *InsertQuery {
+	i.pending = append(i.pending, pendingValue{val: val})
+	return i
+}
 
-	if u.baseQ.opts.BuildLog != nil {
-		u.baseQ.opts.BuildLog(ctx, time.Since(sTime), sql, args, err)
+// toSql calls the BeforeInsert hooks of the pending values and builds the statement
+func (i *InsertQuery) toSql(ctx context.Context) func() (string, []interface{}, error) {
+	return func() (string, []interface{}, error) {
+		for len(i.pending) > 0 {
+			pv := i.pending[0]
+			i.pending = i.pending[1:]
+			val, err := schema.BeforeInsert(ctx, pv.val)
+			if err != nil {
+				i.pending = nil
+				return "", nil, err
+			}
+			if pv.many {
+				i.bi.Values(val)
+			} else {
+				i.bi.Value(val)
+			}
+		}
+		return i.bi.ToSql()
 	}
+}
 
-	if err != nil {
+// Exec executes a SQL statement
+func (u *InsertQuery) Exec(ctx context.Context, lastId *int64) error {
+	info := u.info()
+	if err := u.baseQ.build(ctx, info, u.toSql(ctx)); err != nil {
 		return err
 	}
-	result := u.baseQ.ExecContext(ctx, sql, args...)
+	result := u.baseQ.execStmt(ctx, info, u.baseQ.invoke)
+	var err error
 	*lastId, err = result.LastInsertId()
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// Prepare builds and prepares the statement, the returned query can be executed many times with different named params
+func (u *InsertQuery) Prepare(ctx context.Context) (*PreparedQuery, error) {
+	info := u.info()
+	if err := u.baseQ.build(ctx, info, u.toSql(ctx)); err != nil {
+		return nil, err
+	}
+	return u.baseQ.prepare(ctx, info)
+}
+
+// info generate the description of the statement
+func (u *InsertQuery) info() *options.QueryInfo {
+	return &options.QueryInfo{Op: options.OpInsert, Table: u.bi.TableName()}
+}
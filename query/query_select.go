@@ -2,22 +2,25 @@ package query
 
 import (
 	"context"
-	"time"
 
 	"github.com/rumis/seal/builder"
 	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
 )
 
 // SelectQuery represents the sql builder of select and base query
 type SelectQuery struct {
 	bs    *builder.Select
 	baseQ Query
+
+	// preloads are the relations loaded by AllStruct and OneStruct of the rows
+	preloads []*preload
 }
 
 // NewSelectQuery constructure of SelectQuery
 func NewSelectQuery(b builder.Builder, q Query) *SelectQuery {
 	return &SelectQuery{
-		bs:    builder.NewSelect(b),
+		bs:    builder.NewSelect(b).MaxRows(q.opts.MaxRows).SoftDelete(q.softDelete(false)),
 		baseQ: q,
 	}
 }
@@ -28,6 +31,12 @@ func (s *SelectQuery) Select(cols ...string) *SelectQuery {
 	return s
 }
 
+// AndSelect adds additional columns of the table to be selected, the plain columns are aliased with the table as prefix (class__name) if AliasJoins is set.
+func (s *SelectQuery) AndSelect(table string, cols ...string) *SelectQuery {
+	s.bs.AndSelect(table, cols...)
+	return s
+}
+
 // Agg specifies the aggregate function.
 func (s *SelectQuery) Agg(fn string, col string, alias string, table ...string) *SelectQuery {
 	s.bs.Agg(fn, col, alias, table...)
@@ -47,6 +56,33 @@ func (s *SelectQuery) From(table string) *SelectQuery {
 	return s
 }
 
+// AliasJoins aliases the plain columns of AndSelect and the joins with the table as prefix when the query has joins,
+// so the rows of the joined tables can be decoded into the nested structs tagged with prefix.
+// AllStruct and OneStruct return an error for the models with nested structs if the query joins tables without it.
+func (s *SelectQuery) AliasJoins() *SelectQuery {
+	s.bs.AliasJoins()
+	return s
+}
+
+// Join specifies a JOIN clause, the columns of the joined table are selected as AndSelect does.
+// The "typ" parameter specifies the JOIN type (e.g. "INNER JOIN", "LEFT JOIN").
+func (s *SelectQuery) Join(typ string, table string, on expr.Expr, cols ...string) *SelectQuery {
+	s.bs.Join(typ, table, on, cols...)
+	return s
+}
+
+// InnerJoin specifies an INNER JOIN clause.
+func (s *SelectQuery) InnerJoin(table string, on expr.Expr, cols ...string) *SelectQuery {
+	s.bs.InnerJoin(table, on, cols...)
+	return s
+}
+
+// LeftJoin specifies a LEFT JOIN clause.
+func (s *SelectQuery) LeftJoin(table string, on expr.Expr, cols ...string) *SelectQuery {
+	s.bs.LeftJoin(table, on, cols...)
+	return s
+}
+
 // Where specifies the WHERE condition.
 func (s *SelectQuery) Where(e expr.Expr) *SelectQuery {
 	s.bs.Where(e)
@@ -107,24 +143,56 @@ func (s *SelectQuery) Offset(offset int64) *SelectQuery {
 	return s
 }
 
-// Query queries a SQL statement
-func (s *SelectQuery) Query(ctx context.Context) Rows {
-
-	sTime := time.Now()
+// All allows the query to return all the rows regardless of the MaxRows option
+func (s *SelectQuery) All() *SelectQuery {
+	s.bs.All()
+	return s
+}
 
-	sql, args, err := s.bs.ToSql()
+// WithDeleted includes the soft deleted rows
+func (s *SelectQuery) WithDeleted() *SelectQuery {
+	s.bs.SoftDelete(builder.SoftDelete{})
+	return s
+}
 
-	if s.baseQ.opts.BuildLog != nil {
-		s.baseQ.opts.BuildLog(ctx, time.Since(sTime), sql, args, err)
-	}
+// OnlyDeleted selects only the soft deleted rows of the FROM table, the deleted rows of the joined tables are still excluded
+func (s *SelectQuery) OnlyDeleted() *SelectQuery {
+	s.bs.SoftDelete(s.baseQ.softDelete(true))
+	return s
+}
 
-	if err != nil {
+// Query queries a SQL statement
+func (s *SelectQuery) Query(ctx context.Context) Rows {
+	info := s.info()
+	if err := s.baseQ.build(ctx, info, s.bs.ToSql); err != nil {
 		return NewRows(nil, err)
 	}
-	return s.baseQ.QueryContext(ctx, sql, args...)
+	rows := s.baseQ.queryStmt(ctx, info, s.baseQ.invoke)
+	rows.unaliasedJoins = s.bs.HasJoins() && !s.bs.JoinsAliased()
+	if len(s.preloads) > 0 {
+		q, preloads := s.baseQ, s.preloads
+		rows.preload = func(ctx context.Context, ref interface{}, cols []string) error {
+			return q.loadRelations(ctx, ref, preloads, cols)
+		}
+	}
+	return rows
 }
 
 // ToExpr return the complete sql string. used for sub sql stmt
 func (s *SelectQuery) ToExpr() expr.Expr {
 	return s.bs.ToExpr()
 }
+
+// Prepare builds and prepares the statement, the returned query can be executed many times with different named params
+func (s *SelectQuery) Prepare(ctx context.Context) (*PreparedQuery, error) {
+	info := s.info()
+	if err := s.baseQ.build(ctx, info, s.bs.ToSql); err != nil {
+		return nil, err
+	}
+	return s.baseQ.prepare(ctx, info)
+}
+
+// info generate the description of the statement
+func (s *SelectQuery) info() *options.QueryInfo {
+	return &options.QueryInfo{Op: options.OpSelect, Table: s.bs.TableName()}
+}
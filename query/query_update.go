@@ -2,38 +2,88 @@ package query
 
 import (
 	"context"
-	"time"
+	"errors"
+	"fmt"
 
 	"github.com/rumis/seal/builder"
 	"github.com/rumis/seal/expr"
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/schema"
 )
 
+// ErrStaleObject is returned by the versioned update which affects no rows,
+// the row is changed by others since it is read, or it is deleted
+var ErrStaleObject = errors.New("stale object")
+
 // UpdateQuery represents the sql builder of update and base query
 type UpdateQuery struct {
 	bu    *builder.Update
 	baseQ Query
+
+	// pending is the value set by Value, it is passed to the builder after the BeforeUpdate hook is called
+	pending interface{}
+	// version is set by Version, it overrides the version of the struct value
+	version *versionArg
+}
+
+// versionArg is the arguments of UpdateQuery.Version
+type versionArg struct {
+	col     string
+	current interface{}
 }
 
 // NewUpdateQuery constructure of UpdateQuery
 func NewUpdateQuery(b builder.Builder, q Query) *UpdateQuery {
 	return &UpdateQuery{
-		bu:    builder.NewUpdate(b),
+		bu:    builder.NewUpdate(b).SoftDelete(q.softDelete(false)),
 		baseQ: q,
 	}
 }
 
 // Table set table name
 func (u *UpdateQuery) Table(table string) *UpdateQuery {
-	u.bu.Table(table)
+	u.bu.Table(table).AutoTime(u.baseQ.now, u.baseQ.opts.Timestamps[table])
+	return u
+}
+
+// Only updates only the columns of the value, including the zero fields dropped by omitempty.
+// An unknown column is returned as an error by Exec.
+func (u *UpdateQuery) Only(cols ...string) *UpdateQuery {
+	u.bu.Only(cols...)
+	return u
+}
+
+// Omit drops the columns from the value, an unknown column is returned as an error by Exec
+func (u *UpdateQuery) Omit(cols ...string) *UpdateQuery {
+	u.bu.Omit(cols...)
 	return u
 }
 
-// Value set the data which will be update
+// Value set the data which will be update.
+// The BeforeUpdate hook of the struct is called when the statement is built, and the error aborts the statement.
 func (u *UpdateQuery) Value(val interface{}) *UpdateQuery {
-	u.bu.Value(val)
+	u.pending = val
 	return u
 }
 
+// toSql calls the BeforeUpdate hook of the pending value and builds the statement
+func (u *UpdateQuery) toSql(ctx context.Context) func() (string, []interface{}, error) {
+	return func() (string, []interface{}, error) {
+		if u.pending != nil {
+			val, err := schema.BeforeUpdate(ctx, u.pending)
+			u.pending = nil
+			if err != nil {
+				return "", nil, err
+			}
+			u.bu.Value(val)
+		}
+		if u.version != nil {
+			u.bu.Version(u.version.col, u.version.current)
+		}
+		return u.bu.ToSql()
+	}
+}
+
 // Where  generates a WHERE clause from the given expression.
 func (u *UpdateQuery) Where(e expr.Expr) *UpdateQuery {
 	u.bu.Where(e)
@@ -52,24 +102,60 @@ func (u *UpdateQuery) Or(e expr.Expr) *UpdateQuery {
 	return u
 }
 
-// Exec executes a SQL statement
-func (u *UpdateQuery) Exec(ctx context.Context, cnt *int64) error {
+// All allows the statement to update all the rows when the WHERE clause is not set or always true
+func (u *UpdateQuery) All() *UpdateQuery {
+	u.bu.All()
+	return u
+}
 
-	sTime := time.Now()
+// WithDeleted updates the soft deleted rows too
+func (u *UpdateQuery) WithDeleted() *UpdateQuery {
+	u.bu.SoftDelete(builder.SoftDelete{})
+	return u
+}
 
-	sql, args, err := u.bu.ToSql()
+// OnlyDeleted updates only the soft deleted rows
+func (u *UpdateQuery) OnlyDeleted() *UpdateQuery {
+	u.bu.SoftDelete(u.baseQ.softDelete(true))
+	return u
+}
 
-	if u.baseQ.opts.BuildLog != nil {
-		u.baseQ.opts.BuildLog(ctx, time.Since(sTime), sql, args, err)
-	}
+// Version enables the optimistic locking, only the row whose column equals current is updated and the column is incremented.
+// Exec returns ErrStaleObject if no rows are affected. The struct value with a field tagged with version enables it too,
+// the explicit Version overrides the version of the struct value regardless of the call order.
+func (u *UpdateQuery) Version(col string, current interface{}) *UpdateQuery {
+	u.version = &versionArg{col: col, current: current}
+	return u
+}
 
-	if err != nil {
+// Exec executes a SQL statement, ErrStaleObject is returned if the update is versioned and no rows are affected
+func (u *UpdateQuery) Exec(ctx context.Context, cnt *int64) error {
+	info := u.info()
+	if err := u.baseQ.build(ctx, info, u.toSql(ctx)); err != nil {
 		return err
 	}
-	result := u.baseQ.ExecContext(ctx, sql, args...)
+	result := u.baseQ.execStmt(ctx, info, u.baseQ.invoke)
+	var err error
 	*cnt, err = result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if *cnt == 0 && u.bu.Versioned() {
+		return fmt.Errorf("%w: %s", ErrStaleObject, u.bu.TableName())
+	}
 	return nil
 }
+
+// Prepare builds and prepares the statement, the returned query can be executed many times with different named params
+func (u *UpdateQuery) Prepare(ctx context.Context) (*PreparedQuery, error) {
+	info := u.info()
+	if err := u.baseQ.build(ctx, info, u.toSql(ctx)); err != nil {
+		return nil, err
+	}
+	return u.baseQ.prepare(ctx, info)
+}
+
+// info generate the description of the statement
+func (u *UpdateQuery) info() *options.QueryInfo {
+	return &options.QueryInfo{Op: options.OpUpdate, Table: u.bu.TableName()}
+}
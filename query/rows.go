@@ -1,8 +1,12 @@
 package query
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"sync"
 
+	"github.com/rumis/seal/schema"
 	"github.com/rumis/seal/utils"
 )
 
@@ -11,11 +15,48 @@ import (
 type Rows struct {
 	*sql.Rows
 	err error
+	// ctx is passed to the AfterFind hooks
+	ctx context.Context
+	// preload loads the relations of the decoded structs, cols are the columns of the decoded rows, it is set by SelectQuery.Preload
+	preload func(ctx context.Context, ref interface{}, cols []string) error
+	// unaliasedJoins is set if the query joins tables without AliasJoins, so the rows can not be decoded into the nested fields
+	unaliasedJoins bool
+
+	stat *rowsStat
+}
+
+// rowsStat counts the rows which have been read, done is called once when the rows are closed
+type rowsStat struct {
+	n    int64
+	once sync.Once
+	done func(n int64)
 }
 
 // NewRows generates an Rows instance with *sql.Rows and error which come from db.Query.
 func NewRows(rows *sql.Rows, err error) Rows {
-	return Rows{rows, err}
+	return Rows{Rows: rows, err: err}
+}
+
+// Next prepares the next result row for reading with the Scan method.
+func (r Rows) Next() bool {
+	if !r.Rows.Next() {
+		return false
+	}
+	if r.stat != nil {
+		r.stat.n++
+	}
+	return true
+}
+
+// Close closes the Rows, preventing further enumeration.
+func (r Rows) Close() error {
+	err := r.Rows.Close()
+	if r.stat != nil {
+		r.stat.once.Do(func() {
+			r.stat.done(r.stat.n)
+		})
+	}
+	return err
 }
 
 // AllMap scan all rows and convert to map slice
@@ -23,7 +64,7 @@ func (r Rows) AllMap() ([]map[string]interface{}, error) {
 	if r.err != nil {
 		return nil, r.err
 	}
-	defer r.Rows.Close()
+	defer r.Close()
 	cols, err := r.Columns()
 	if err != nil {
 		return nil, err
@@ -47,25 +88,37 @@ func (r Rows) AllMap() ([]map[string]interface{}, error) {
 	return rows, r.Close()
 }
 
-// AllStruct scan all rows and convert to struct slice
+// AllStruct scan all rows and convert to struct slice, the preloaded relations are loaded and the AfterFind hook of each struct is called
 func (r Rows) AllStruct(ref interface{}) error {
 	rows, err := r.AllMap()
 	if err != nil {
 		return err
 	}
+	var cols []string
+	if len(rows) > 0 {
+		cols = rowColumns(rows[0])
+	}
+	if rows, err = r.nestRows(ref, rows); err != nil {
+		return err
+	}
 	err = utils.Map2Struct(rows, ref)
 	if err != nil {
 		return err
 	}
-	return nil
+	if r.preload != nil {
+		if err := r.preload(r.context(), ref, cols); err != nil {
+			return err
+		}
+	}
+	return schema.AfterFind(r.context(), ref)
 }
 
-// OneMap scan one row and convert to map
+// OneMap scan one row and convert to map, an empty map and nil error are returned if no row is found
 func (r Rows) OneMap() (map[string]interface{}, error) {
 	if r.err != nil {
 		return nil, r.err
 	}
-	defer r.Rows.Close()
+	defer r.Close()
 	cols, err := r.Columns()
 	if err != nil {
 		return nil, err
@@ -92,17 +145,67 @@ func (r Rows) OneMap() (map[string]interface{}, error) {
 	return rowMap, r.Close()
 }
 
-// OneStruct scan one row and convert to struct
+// OneStruct scan one row and convert to struct, the preloaded relations are loaded and the AfterFind hook is called if the row is found.
+// ref is left unchanged and nil error is returned if no row is found, use Model.Find to get ErrNoRows.
 func (r Rows) OneStruct(ref interface{}) error {
 	row, err := r.OneMap()
 	if err != nil {
 		return err
 	}
-	err = utils.Map2Struct(row, ref)
+	if len(row) == 0 {
+		return utils.Map2Struct(row, ref)
+	}
+	cols := rowColumns(row)
+	rows, err := r.nestRows(ref, []map[string]interface{}{row})
 	if err != nil {
 		return err
 	}
-	return nil
+	err = utils.Map2Struct(rows[0], ref)
+	if err != nil {
+		return err
+	}
+	if r.preload != nil {
+		if err := r.preload(r.context(), ref, cols); err != nil {
+			return err
+		}
+	}
+	return schema.AfterFind(r.context(), ref)
+}
+
+// rowColumns return the columns of the row
+func rowColumns(row map[string]interface{}) []string {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	return cols
+}
+
+// nestRows groups the columns of the nested fields of the model into maps, see schema.Nest.
+// The rows are returned as is if ref is not a model.
+// It is an error if the model has nested fields and the query joins tables without AliasJoins.
+func (r Rows) nestRows(ref interface{}, rows []map[string]interface{}) ([]map[string]interface{}, error) {
+	s, err := schema.Parse(ref)
+	if err != nil || len(s.Nested) == 0 {
+		return rows, nil
+	}
+	if r.unaliasedJoins {
+		return nil, fmt.Errorf("the columns of the joins are not aliased, call AliasJoins to decode them into the nested fields of %s", s.Type)
+	}
+	for i, row := range rows {
+		if rows[i], err = s.Nest(row); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// context return the context of the query
+func (r Rows) context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
 }
 
 // Agg scan and return the aggregate result
@@ -110,7 +213,7 @@ func (r Rows) Agg(ref interface{}) error {
 	if r.err != nil {
 		return r.err
 	}
-	defer r.Rows.Close()
+	defer r.Close()
 	if !r.Next() {
 		if err := r.Err(); err != nil {
 			return err
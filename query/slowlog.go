@@ -0,0 +1,42 @@
+package query
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/rumis/seal/options"
+)
+
+// explainTimeout is the max time of running EXPLAIN for a slow statement
+const explainTimeout = 5 * time.Second
+
+// logSlowQuery sends the slow statement to the slow query log if it is sampled.
+// If the explain is enabled, the statement is explained on the root executor in a separate goroutine,
+// so the connection of the statement which may still be in use is not required.
+func (q Query) logSlowQuery(ctx context.Context, info *options.QueryInfo, ts time.Duration, err error) {
+	rate := q.opts.SlowQuerySampleRate
+	if rate > 0 && rate < 1 && rand.Float64() >= rate {
+		return
+	}
+	entry := options.SlowQuery{
+		Op:       info.Op,
+		Table:    info.Table,
+		SQL:      info.SQL,
+		Args:     info.Args,
+		Duration: ts,
+		Err:      err,
+	}
+	// raw statements may not be explainable, eg. DDL
+	if !q.opts.SlowQueryExplain || err != nil || info.Op == options.OpExec {
+		q.opts.SlowQueryLog(ctx, entry)
+		return
+	}
+	go func() {
+		ectx, cancel := context.WithTimeout(context.Background(), explainTimeout)
+		defer cancel()
+		rows, err := q.root.QueryContext(ectx, q.b.Explain(entry.SQL), entry.Args...)
+		entry.Plan, entry.ExplainErr = NewRows(rows, err).AllMap()
+		q.opts.SlowQueryLog(ctx, entry)
+	}()
+}
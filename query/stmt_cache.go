@@ -0,0 +1,116 @@
+package query
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// StmtCache is a LRU cache of prepared statements keyed by the sql string.
+// Statements are closed when they are evicted and no longer in use.
+type StmtCache struct {
+	e    Executor
+	size int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// cachedStmt is the entry of StmtCache
+type cachedStmt struct {
+	sql     string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// NewStmtCache generate a cache which holds at most size statements prepared by the executor
+func NewStmtCache(e Executor, size int) *StmtCache {
+	return &StmtCache{
+		e:     e,
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get return the prepared statement of the sql, the statement is prepared if it is not cached.
+// The release func must be called when the statement is no longer used.
+func (c *StmtCache) Get(ctx context.Context, sql string) (*sql.Stmt, func(), error) {
+	c.mu.Lock()
+	if el, ok := c.items[sql]; ok {
+		c.ll.MoveToFront(el)
+		cs := el.Value.(*cachedStmt)
+		cs.refs++
+		c.mu.Unlock()
+		return cs.stmt, c.releaseFunc(cs), nil
+	}
+	c.mu.Unlock()
+
+	stmt, err := c.e.PrepareContext(ctx, sql)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[sql]; ok {
+		// prepared concurrently, keep the cached one
+		_ = stmt.Close()
+		c.ll.MoveToFront(el)
+		cs := el.Value.(*cachedStmt)
+		cs.refs++
+		return cs.stmt, c.releaseFunc(cs), nil
+	}
+	cs := &cachedStmt{sql: sql, stmt: stmt, refs: 1}
+	c.items[sql] = c.ll.PushFront(cs)
+	for c.ll.Len() > c.size {
+		c.evict(c.ll.Back())
+	}
+	return cs.stmt, c.releaseFunc(cs), nil
+}
+
+// Len return the count of cached statements
+func (c *StmtCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// Close evict all the statements
+func (c *StmtCache) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.ll.Len() > 0 {
+		c.evict(c.ll.Back())
+	}
+}
+
+// evict remove the entry from cache, the statement is closed if it is not in use.
+// must be called with c.mu held
+func (c *StmtCache) evict(el *list.Element) {
+	cs := el.Value.(*cachedStmt)
+	c.ll.Remove(el)
+	delete(c.items, cs.sql)
+	cs.evicted = true
+	if cs.refs == 0 {
+		_ = cs.stmt.Close()
+	}
+}
+
+// releaseFunc generate the func which release a reference of the statement
+func (c *StmtCache) releaseFunc(cs *cachedStmt) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			cs.refs--
+			if cs.evicted && cs.refs == 0 {
+				_ = cs.stmt.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafetyGuard(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile, options.WithMaxRows(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var id, cnt int64
+	for i := 0; i < 3; i++ {
+		if err := db.Insert("class").Value(Class{Name: randString(8)}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = db.Select("id").From("class").Query(ctx).AllMap()
+	var se *SafetyError
+	assert.True(t, errors.As(err, &se))
+	assert.Equal(t, "select", se.Op)
+
+	rows, err := db.Select("id").From("class").Limit(2).Query(ctx).AllMap()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+
+	rows, err = db.Select("id").From("class").All().Query(ctx).AllMap()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+
+	var total int64
+	err = db.Count("id").From("class").Query(ctx).Agg(&total)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+
+	err = db.Delete("class").Where(NotIn("id")).Exec(ctx, &cnt)
+	assert.True(t, errors.Is(err, ErrUnsafeStatement))
+	err = db.Update("class").Value(map[string]interface{}{"name": "x"}).Exec(ctx, &cnt)
+	assert.True(t, errors.Is(err, ErrUnsafeStatement))
+
+	err = db.Delete("class").All().Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), cnt)
+}
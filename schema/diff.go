@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Snapshot return a deep copy of the struct value v, the values referenced by the pointer, slice and map fields are copied too,
+// so the changes made through them are found by Diff. The unexported fields are copied shallowly.
+func Snapshot(v reflect.Value) reflect.Value {
+	return deepCopy(reflect.Indirect(v), make(map[uintptr]reflect.Value))
+}
+
+// deepCopy copies the value recursively, copied keeps the copies of the pointers to keep the cycles
+func deepCopy(v reflect.Value, copied map[uintptr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := copied[v.Pointer()]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		copied[v.Pointer()] = c
+		c.Elem().Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value(), copied))
+		}
+		return c
+	case reflect.Array, reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		if v.Kind() == reflect.Array {
+			for i := 0; i < v.Len(); i++ {
+				c.Index(i).Set(deepCopy(v.Index(i), copied))
+			}
+			return c
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if c.Field(i).CanSet() {
+				c.Field(i).Set(deepCopy(v.Field(i), copied))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// Diff return the columns whose fields are different between the struct values before and after,
+// the values of the map are the fields of after, including the zero values.
+func (s *Schema) Diff(before, after reflect.Value) map[string]interface{} {
+	before, after = reflect.Indirect(before), reflect.Indirect(after)
+	changed := make(map[string]interface{})
+	for _, f := range s.Fields {
+		bv, av := f.Interface(before), f.Interface(after)
+		if !reflect.DeepEqual(bv, av) {
+			changed[f.Column] = av
+		}
+	}
+	return changed
+}
+
+// Changes return the columns changed from before to after, see Schema.Diff.
+// before and after must be the structs or pointers to structs of the same type.
+func Changes(before, after interface{}) (map[string]interface{}, error) {
+	bv, av := reflect.Indirect(reflect.ValueOf(before)), reflect.Indirect(reflect.ValueOf(after))
+	if !bv.IsValid() || !av.IsValid() || bv.Type() != av.Type() {
+		return nil, fmt.Errorf("schema: cannot diff %T and %T", before, after)
+	}
+	s, err := ParseType(av.Type())
+	if err != nil {
+		return nil, err
+	}
+	return s.Diff(bv, av), nil
+}
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"context"
+	"reflect"
+)
+
+// BeforeInserter is implemented by the models which are validated or normalized before they are inserted,
+// the insert is aborted if it returns an error
+type BeforeInserter interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// BeforeUpdater is implemented by the models which are validated or normalized before they are updated,
+// the update is aborted if it returns an error
+type BeforeUpdater interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterFinder is implemented by the models which are post processed after they are decoded from the rows,
+// the error is returned by the query
+type AfterFinder interface {
+	AfterFind(ctx context.Context) error
+}
+
+// BeforeInsert calls the BeforeInsert hook of v, see CallHook
+func BeforeInsert(ctx context.Context, v interface{}) (interface{}, error) {
+	return CallHook(v, func(h interface{}) (bool, error) {
+		if hook, ok := h.(BeforeInserter); ok {
+			return true, hook.BeforeInsert(ctx)
+		}
+		return false, nil
+	})
+}
+
+// BeforeUpdate calls the BeforeUpdate hook of v, see CallHook
+func BeforeUpdate(ctx context.Context, v interface{}) (interface{}, error) {
+	return CallHook(v, func(h interface{}) (bool, error) {
+		if hook, ok := h.(BeforeUpdater); ok {
+			return true, hook.BeforeUpdate(ctx)
+		}
+		return false, nil
+	})
+}
+
+// AfterFind calls the AfterFind hook of v, see CallHook
+func AfterFind(ctx context.Context, v interface{}) error {
+	_, err := CallHook(v, func(h interface{}) (bool, error) {
+		if hook, ok := h.(AfterFinder); ok {
+			return true, hook.AfterFind(ctx)
+		}
+		return false, nil
+	})
+	return err
+}
+
+// CallHook calls the hook on each struct of v, v can be a struct, a pointer to struct, a slice of them or a pointer to the slice.
+// call reports whether the struct implements the hook. The structs in a slice or behind a pointer are changed in place,
+// a struct passed by value is copied to call the hook with a pointer receiver, and the copy is returned instead of v.
+// The first error stops calling the remaining hooks.
+func CallHook(v interface{}, call func(h interface{}) (bool, error)) (interface{}, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return v, nil
+		}
+		if rv.Elem().Kind() == reflect.Slice {
+			return v, callSlice(rv.Elem(), call)
+		}
+		_, err := call(v)
+		return v, err
+	case reflect.Struct:
+		p := reflect.New(rv.Type())
+		p.Elem().Set(rv)
+		ok, err := call(p.Interface())
+		if !ok {
+			return v, nil
+		}
+		return p.Elem().Interface(), err
+	case reflect.Slice:
+		return v, callSlice(rv, call)
+	}
+	return v, nil
+}
+
+// callSlice calls the hook on each element of the slice
+func callSlice(rv reflect.Value, call func(h interface{}) (bool, error)) error {
+	for i := 0; i < rv.Len(); i++ {
+		el := rv.Index(i)
+		if el.Kind() != reflect.Ptr {
+			el = el.Addr()
+		} else if el.IsNil() {
+			continue
+		}
+		if _, err := call(el.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/rumis/seal/expr"
+)
+
+// parseNested adds the field to the nested fields of the schema
+func (s *Schema) parseNested(f *Field, prefix string) error {
+	t := f.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("schema: prefixed field %s must be a struct, got %s", f.Name, f.Type)
+	}
+	f.Prefix = prefix
+	s.Nested = append(s.Nested, f)
+	return nil
+}
+
+// Nest groups the columns of the nested fields into maps, so the row can be decoded into the model.
+// The columns of a nested field are the ones aliased with its column and expr.PrefixSeparator (class__name),
+// or starting with its prefix (class_name) and not being a column of the model.
+// The nested field is left out if all its columns are NULL and it is a pointer, so it stays nil for the missing side of a LEFT JOIN.
+// The row is returned as is if the model has no nested fields.
+func (s *Schema) Nest(row map[string]interface{}) (map[string]interface{}, error) {
+	if len(s.Nested) == 0 {
+		return row, nil
+	}
+	out := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		out[k] = v
+	}
+	for _, f := range s.Nested {
+		sub := make(map[string]interface{})
+		null := true
+		for k, v := range row {
+			col, ok := s.nestedColumn(f, k)
+			if !ok {
+				continue
+			}
+			sub[col] = v
+			if v != nil {
+				null = false
+			}
+		}
+		if len(sub) == 0 || null && f.Type.Kind() == reflect.Ptr {
+			continue
+		}
+		ns, err := ParseType(f.Type)
+		if err != nil {
+			return nil, err
+		}
+		if sub, err = ns.Nest(sub); err != nil {
+			return nil, err
+		}
+		out[f.Column] = sub
+	}
+	return out, nil
+}
+
+// nestedColumn return the column of the nested field which the key of the row is mapped to
+func (s *Schema) nestedColumn(f *Field, key string) (string, bool) {
+	if alias := f.Column + expr.PrefixSeparator; strings.HasPrefix(key, alias) && len(key) > len(alias) {
+		return key[len(alias):], true
+	}
+	if f.Prefix == "" || !strings.HasPrefix(key, f.Prefix) || len(key) == len(f.Prefix) {
+		return "", false
+	}
+	if _, ok := s.columns[key]; ok {
+		return "", false
+	}
+	return key[len(f.Prefix):], true
+}
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// kinds of the relations, the option of the tag is the kind with the foreign key, e.g. `seal:"class,belongs_to=class_id"`
+const (
+	// BelongsTo the foreign key is in the model and references the primary key of the related model
+	BelongsTo = "belongs_to"
+	// HasOne the foreign key is in the related model and references the primary key of the model
+	HasOne = "has_one"
+	// HasMany is HasOne with many related models
+	HasMany = "has_many"
+)
+
+// Relation is a field which holds the related models, it is not a column of the table
+type Relation struct {
+	Kind string
+	// Field is the struct field, it is a pointer to struct or a struct for BelongsTo and HasOne, a slice for HasMany
+	Field *Field
+	// ForeignKey is the column in the model for BelongsTo, and in the related model for HasOne and HasMany
+	ForeignKey string
+	// Type is the struct type of the related model
+	Type reflect.Type
+}
+
+// Relation return the relation of the struct field name, nil if it is not found
+func (s *Schema) Relation(name string) *Relation {
+	for _, r := range s.Relations {
+		if r.Field.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// StripRelations removes the relation and nested fields from the map converted from the model
+func (s *Schema) StripRelations(m map[string]interface{}) {
+	for _, r := range s.Relations {
+		delete(m, r.Field.Column)
+	}
+	for _, f := range s.Nested {
+		delete(m, f.Column)
+	}
+}
+
+// OptionValue return the value of the option in the form of key=value, ok is false if the option is not set
+func (f *Field) OptionValue(key string) (string, bool) {
+	for _, o := range f.Options {
+		if strings.HasPrefix(o, key+"=") {
+			return o[len(key)+1:], true
+		}
+	}
+	return "", false
+}
+
+// parseRelation parses the relation of the field, nil is returned if it is not a relation
+func parseRelation(f *Field) (*Relation, error) {
+	for _, kind := range []string{BelongsTo, HasOne, HasMany} {
+		fk, ok := f.OptionValue(kind)
+		if !ok {
+			continue
+		}
+		if fk == "" {
+			return nil, fmt.Errorf("schema: no foreign key of the %s field %s", kind, f.Name)
+		}
+		t := f.Type
+		if kind == HasMany {
+			if t.Kind() != reflect.Slice {
+				return nil, fmt.Errorf("schema: has_many field %s must be a slice, got %s", f.Name, t)
+			}
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("schema: %s field %s must hold structs, got %s", kind, f.Name, f.Type)
+		}
+		return &Relation{Kind: kind, Field: f, ForeignKey: fk, Type: t}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,303 @@
+// Package schema parses the struct models into tables and columns.
+//
+// The column of a field is the name in its seal tag, the options after the name describe the column:
+//
+//	type User struct {
+//		ID   int64  `seal:"id,pk,autoincr"`
+//		Name string `seal:"name"`
+//	}
+//
+// The table is returned by the TableName method of the model, or the snake case of the struct name.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TagName is the struct tag of the columns
+const TagName = "seal"
+
+// options of the tag
+const (
+	OptionPK       = "pk"
+	OptionAutoIncr = "autoincr"
+	OptionSquash   = "squash"
+	// OptionAutoCreateTime fills the column with the current time on insert if it is zero
+	OptionAutoCreateTime = "autocreatetime"
+	// OptionAutoUpdateTime fills the column with the current time on insert if it is zero, and on every update
+	OptionAutoUpdateTime = "autoupdatetime"
+	// OptionSoftDelete declares the deleted time column, the row is soft deleted when it is not NULL
+	OptionSoftDelete = "softdelete"
+	// OptionVersion declares the version column of the optimistic locking
+	OptionVersion = "version"
+	// OptionPrefix declares a nested struct which is decoded from the columns with the prefix, e.g. `seal:"class,prefix=class_"`
+	OptionPrefix = "prefix"
+)
+
+// Tabler is implemented by the models which declare their table
+type Tabler interface {
+	TableName() string
+}
+
+// Field is a column of the model
+type Field struct {
+	// Name is the name of the struct field
+	Name   string
+	Column string
+	// Index is the index sequence of the field for reflect.Value.FieldByIndex
+	Index []int
+	Type  reflect.Type
+	// Options are the options in the tag after the column name
+	Options []string
+
+	PK             bool
+	AutoIncr       bool
+	AutoCreateTime bool
+	AutoUpdateTime bool
+	SoftDelete     bool
+	Version        bool
+	// Prefix is the prefix of the columns of the nested struct
+	Prefix string
+}
+
+// HasOption reports whether the option is set in the tag
+func (f *Field) HasOption(opt string) bool {
+	for _, o := range f.Options {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// Value return the value of the field in the struct value v,
+// the Value is invalid if the field is in a squashed struct pointer which is nil
+func (f *Field) Value(v reflect.Value) reflect.Value {
+	for i, x := range f.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// Interface return the value of the field in the struct value v, nil if the field is in a nil squashed struct pointer
+func (f *Field) Interface(v reflect.Value) interface{} {
+	fv := f.Value(v)
+	if !fv.IsValid() {
+		return nil
+	}
+	return fv.Interface()
+}
+
+// IsZero reports whether the field in the struct value v is zero, the field in a nil squashed struct pointer is zero
+func (f *Field) IsZero(v reflect.Value) bool {
+	fv := f.Value(v)
+	return !fv.IsValid() || fv.IsZero()
+}
+
+// Schema describes the table of a model
+type Schema struct {
+	Type   reflect.Type
+	Table  string
+	Fields []*Field
+	// PK is the primary key, it is the field tagged with pk, or the "id" column if none is tagged
+	PK *Field
+	// SoftDelete is the deleted time column tagged with softdelete, nil if the model is not soft deleted
+	SoftDelete *Field
+	// Version is the version column tagged with version, nil if the model is not versioned
+	Version *Field
+	// Relations are the fields tagged with belongs_to, has_one or has_many, they are not in Fields
+	Relations []*Relation
+	// Nested are the struct fields tagged with prefix, they are not in Fields
+	Nested []*Field
+
+	columns map[string]*Field
+}
+
+// Field return the field of the column, nil if the column is not found
+func (s *Schema) Field(column string) *Field {
+	return s.columns[column]
+}
+
+// Columns return the columns in the order of the fields
+func (s *Schema) Columns() []string {
+	cols := make([]string, len(s.Fields))
+	for i, f := range s.Fields {
+		cols[i] = f.Column
+	}
+	return cols
+}
+
+// ErrNoPrimaryKey is returned when the operation requires the primary key but the model has none
+var ErrNoPrimaryKey = errors.New("model has no primary key")
+
+// cache of the parsed schemas keyed by the struct type
+var cache sync.Map
+
+// Parse parses the schema of the model, model can be a struct, a pointer to struct or a slice of them.
+// The schemas are cached by type, so TableName must return the same table for all the values of a type.
+func Parse(model interface{}) (*Schema, error) {
+	if model == nil {
+		return nil, errors.New("schema: nil model")
+	}
+	return ParseType(reflect.TypeOf(model))
+}
+
+// ParseType parses the schema of the struct type, the pointers and slices are dereferenced
+func ParseType(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema: model must be a struct, got %s", t)
+	}
+	if s, ok := cache.Load(t); ok {
+		return s.(*Schema), nil
+	}
+	s := &Schema{
+		Type:    t,
+		Table:   tableName(t),
+		columns: make(map[string]*Field),
+	}
+	if err := s.parseFields(t, nil); err != nil {
+		return nil, err
+	}
+	if s.PK == nil {
+		if f := s.columns["id"]; f != nil {
+			f.PK = true
+			f.AutoIncr = isInt(f.Type)
+			s.PK = f
+		}
+	}
+	actual, _ := cache.LoadOrStore(t, s)
+	return actual.(*Schema), nil
+}
+
+// parseFields parses the fields of the struct, index is the index sequence of the struct in the model
+func (s *Schema) parseFields(t reflect.Type, index []int) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		tag := sf.Tag.Get(TagName)
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		f := &Field{
+			Name:    sf.Name,
+			Column:  parts[0],
+			Index:   append(append([]int{}, index...), i),
+			Type:    sf.Type,
+			Options: parts[1:],
+		}
+		if f.Column == "" {
+			f.Column = sf.Name
+		}
+		if f.HasOption(OptionSquash) {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() != reflect.Struct {
+				return fmt.Errorf("schema: cannot squash non-struct field %s of %s", sf.Name, s.Type)
+			}
+			if err := s.parseFields(ft, f.Index); err != nil {
+				return err
+			}
+			continue
+		}
+		rel, err := parseRelation(f)
+		if err != nil {
+			return err
+		}
+		if rel != nil {
+			s.Relations = append(s.Relations, rel)
+			continue
+		}
+		if prefix, ok := f.OptionValue(OptionPrefix); ok {
+			if err := s.parseNested(f, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+		f.PK = f.HasOption(OptionPK)
+		f.AutoIncr = f.HasOption(OptionAutoIncr)
+		f.AutoCreateTime = f.HasOption(OptionAutoCreateTime)
+		f.AutoUpdateTime = f.HasOption(OptionAutoUpdateTime)
+		f.SoftDelete = f.HasOption(OptionSoftDelete)
+		f.Version = f.HasOption(OptionVersion)
+		if f.PK {
+			if s.PK != nil {
+				return fmt.Errorf("schema: %s has more than one primary key", s.Type)
+			}
+			s.PK = f
+		}
+		if f.SoftDelete {
+			if s.SoftDelete != nil {
+				return fmt.Errorf("schema: %s has more than one soft delete column", s.Type)
+			}
+			s.SoftDelete = f
+		}
+		if f.Version {
+			if s.Version != nil {
+				return fmt.Errorf("schema: %s has more than one version column", s.Type)
+			}
+			s.Version = f
+		}
+		if _, ok := s.columns[f.Column]; ok {
+			return fmt.Errorf("schema: duplicate column %s in %s", f.Column, s.Type)
+		}
+		s.columns[f.Column] = f
+		s.Fields = append(s.Fields, f)
+	}
+	return nil
+}
+
+// tableName return the table declared by TableName, or the snake case of the type name
+func tableName(t reflect.Type) string {
+	if tabler, ok := reflect.New(t).Interface().(Tabler); ok {
+		return tabler.TableName()
+	}
+	if tabler, ok := reflect.New(t).Elem().Interface().(Tabler); ok {
+		return tabler.TableName()
+	}
+	return snakeCase(t.Name())
+}
+
+// snakeCase converts the name like "UserProfile" or "HTTPLog" to "user_profile" or "http_log"
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var s strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				s.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		s.WriteRune(r)
+	}
+	return s.String()
+}
+
+// isInt reports whether the type is an integer
+func isInt(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Base struct {
+	ID        uint64    `seal:"id"`
+	CreatedAt time.Time `seal:"created_at"`
+}
+
+type UserProfile struct {
+	Base     `seal:",squash"`
+	Nickname string `seal:"nickname,omitempty"`
+	Avatar   string
+	Ignored  string `seal:"-"`
+	private  string
+}
+
+type HTTPLog struct {
+	Code int `seal:"code,pk"`
+}
+
+func (l *HTTPLog) TableName() string {
+	return "http_logs"
+}
+
+func TestParse(t *testing.T) {
+	s, err := Parse(&UserProfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "user_profile", s.Table)
+	assert.Equal(t, []string{"id", "created_at", "nickname", "Avatar"}, s.Columns())
+	assert.Equal(t, "id", s.PK.Column)
+	assert.True(t, s.PK.AutoIncr)
+	assert.Equal(t, []int{0, 0}, s.PK.Index)
+	assert.True(t, s.Field("nickname").HasOption("omitempty"))
+	assert.Nil(t, s.Field("Ignored"))
+
+	v := reflect.ValueOf(UserProfile{Base: Base{ID: 3}})
+	assert.Equal(t, uint64(3), s.PK.Value(v).Interface())
+
+	// cached
+	s2, _ := Parse([]UserProfile{})
+	assert.True(t, s == s2)
+
+	s, err = Parse(HTTPLog{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "http_logs", s.Table)
+	assert.Equal(t, "code", s.PK.Column)
+	assert.False(t, s.PK.AutoIncr)
+
+	_, err = Parse(1)
+	assert.Error(t, err)
+	_, err = Parse(struct {
+		A int `seal:"a,pk"`
+		B int `seal:"b,pk"`
+	}{})
+	assert.Error(t, err)
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "user_profile", snakeCase("UserProfile"))
+	assert.Equal(t, "http_log", snakeCase("HTTPLog"))
+	assert.Equal(t, "user_id", snakeCase("UserID"))
+	assert.Equal(t, "log2_event", snakeCase("Log2Event"))
+}
+
+func TestChanges(t *testing.T) {
+	before := UserProfile{Base: Base{ID: 1}, Nickname: "murong", Avatar: "a.png"}
+	after := Snapshot(reflect.ValueOf(&before)).Interface().(UserProfile)
+	after.Nickname = ""
+	after.CreatedAt = time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+
+	changed, err := Changes(before, &after)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"nickname": "", "created_at": after.CreatedAt}, changed)
+	assert.Equal(t, "murong", before.Nickname)
+
+	_, err = Changes(before, HTTPLog{})
+	assert.Error(t, err)
+
+	// the snapshot does not share the pointer, slice and map fields
+	type doc struct {
+		Title *string           `seal:"title"`
+		Tags  []string          `seal:"tags"`
+		Attrs map[string]string `seal:"attrs"`
+	}
+	title := "a"
+	d := doc{Title: &title, Tags: []string{"x"}, Attrs: map[string]string{"k": "v"}}
+	snap := Snapshot(reflect.ValueOf(&d)).Interface().(doc)
+	*d.Title = "b"
+	d.Tags[0] = "y"
+	d.Attrs["k"] = "w"
+	changed, err = Changes(snap, d)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"title": d.Title, "tags": d.Tags, "attrs": d.Attrs}, changed)
+	assert.Equal(t, "a", *snap.Title)
+}
+
+type hooked struct {
+	Name  string
+	Calls int
+}
+
+func (h *hooked) BeforeInsert(ctx context.Context) error {
+	if h.Name == "" {
+		return errors.New("name is required")
+	}
+	h.Calls++
+	return nil
+}
+
+func TestCallHook(t *testing.T) {
+	ctx := context.Background()
+
+	// the struct passed by value is copied
+	v := hooked{Name: "murong"}
+	got, err := BeforeInsert(ctx, v)
+	assert.Nil(t, err)
+	assert.Equal(t, hooked{Name: "murong", Calls: 1}, got)
+	assert.Equal(t, 0, v.Calls)
+
+	// the structs in the slice are changed in place
+	vs := []hooked{{Name: "murong"}, {Name: "xiaoyao"}}
+	_, err = BeforeInsert(ctx, vs)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 1}, []int{vs[0].Calls, vs[1].Calls})
+
+	ps := []*hooked{{Name: "murong"}, {}}
+	_, err = BeforeInsert(ctx, &ps)
+	assert.EqualError(t, err, "name is required")
+	assert.Equal(t, 1, ps[0].Calls)
+
+	// the values without hooks are returned as they are
+	m := map[string]interface{}{"name": "murong"}
+	got, err = BeforeInsert(ctx, m)
+	assert.Nil(t, err)
+	assert.Equal(t, m, got)
+	assert.Nil(t, AfterFind(ctx, &v))
+}
+
+type Post struct {
+	ID     int64  `seal:"id"`
+	UserID int64  `seal:"user_id"`
+	Author *Owner `seal:"author,belongs_to=user_id"`
+}
+
+type Owner struct {
+	ID    int64  `seal:"id"`
+	Posts []Post `seal:"posts,has_many=user_id"`
+}
+
+func TestRelations(t *testing.T) {
+	s, err := Parse(&Owner{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id"}, s.Columns())
+	rel := s.Relation("Posts")
+	assert.Equal(t, HasMany, rel.Kind)
+	assert.Equal(t, "user_id", rel.ForeignKey)
+	assert.Equal(t, reflect.TypeOf(Post{}), rel.Type)
+
+	s, err = Parse(Post{})
+	assert.Nil(t, err)
+	assert.Equal(t, BelongsTo, s.Relation("Author").Kind)
+	m := map[string]interface{}{"id": 1, "author": nil}
+	s.StripRelations(m)
+	assert.Equal(t, map[string]interface{}{"id": 1}, m)
+
+	_, err = Parse(struct {
+		Posts Post `seal:"posts,has_many=user_id"`
+	}{})
+	assert.Error(t, err)
+}
+
+func TestNest(t *testing.T) {
+	type class struct {
+		ID   int    `seal:"id"`
+		Name string `seal:"name"`
+	}
+	s, err := Parse(struct {
+		ID      int    `seal:"id"`
+		ClassID int    `seal:"class_id"`
+		Class   *class `seal:"class,prefix=class_"`
+	}{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "class_id"}, s.Columns())
+	assert.Equal(t, "class_", s.Nested[0].Prefix)
+
+	row, err := s.Nest(map[string]interface{}{"id": 1, "class_id": 2, "class__id": 2, "class_name": "A"})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"id": 2, "name": "A"}, row["class"])
+	assert.Equal(t, 2, row["class_id"])
+
+	// all NULL
+	row, err = s.Nest(map[string]interface{}{"id": 1, "class_id": 0, "class__id": nil, "class__name": nil})
+	assert.Nil(t, err)
+	assert.NotContains(t, row, "class")
+
+	_, err = Parse(struct {
+		Class int `seal:"class,prefix=class_"`
+	}{})
+	assert.Error(t, err)
+}
+
+func TestNilSquashPointer(t *testing.T) {
+	type profile struct {
+		*Base    `seal:",squash"`
+		Nickname string `seal:"nickname"`
+	}
+	s, err := Parse(profile{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"id", "created_at", "nickname"}, s.Columns())
+
+	v := reflect.ValueOf(profile{Nickname: "m"})
+	assert.False(t, s.PK.Value(v).IsValid())
+	assert.Nil(t, s.PK.Interface(v))
+	assert.True(t, s.PK.IsZero(v))
+	assert.Equal(t, "m", s.Field("nickname").Interface(v))
+
+	before := reflect.ValueOf(profile{Base: &Base{ID: 1}, Nickname: "m"})
+	assert.Equal(t, map[string]interface{}{"id": nil, "created_at": nil}, s.Diff(before, v))
+	assert.Empty(t, s.Diff(v, v))
+}
@@ -11,6 +11,7 @@ import (
 // Open opens a database specified by a driver name and data source name (DSN).
 // Note that Open does not check if DSN is specified correctly. It doesn't try to establish a DB connection either.
 // Please refer to sql.Open() for more information.
+// The errors are translated by the translator of the driver name, see sqlerr.Register.
 func Open(driverName string, sourceName string, opts ...options.SealOptionsFunc) (DB, error) {
 	db, err := sql.Open(driverName, sourceName)
 	if err != nil {
@@ -28,19 +29,46 @@ func Open(driverName string, sourceName string, opts ...options.SealOptionsFunc)
 	default:
 		b = builder.NewStandardBuilder()
 	}
-	return OpenWithDB(db, b, opts...)
+	return OpenWithDB(db, b, append([]options.SealOptionsFunc{options.WithDriver(driverName)}, opts...)...)
 }
 
 // OpenWithDB Create a new DB instance.
 // Note that Open does not check if DSN is specified correctly. It doesn't try to establish a DB connection either.
 // Please refer to sql.Open() for more information.
+// The errors are translated by the dialect of the builder, use options.WithDriver to translate them by another driver name.
 func OpenWithDB(db *sql.DB, b builder.Builder, opts ...options.SealOptionsFunc) (DB, error) {
+	return OpenWithExecutor(db, b, opts...)
+}
+
+// OpenWithExecutor Create a new DB instance which runs the statements on the executor, eg. a mock for tests.
+// Transactions and pool stats are only available if the executor provides BeginTx, Stats and Close like *sql.DB.
+// The errors are translated as OpenWithDB does.
+func OpenWithExecutor(e query.Executor, b builder.Builder, opts ...options.SealOptionsFunc) (DB, error) {
 	cfg := options.DefaultSealOptions()
 	for _, fn := range opts {
 		fn(cfg)
 	}
+	sdb := DB{
+		Query: query.NewQuery(b, e, cfg),
+	}
+	if de, ok := e.(dbExecutor); ok {
+		sdb.sqlDB = de
+		if cfg.Metrics != nil && cfg.PoolStatsInterval > 0 {
+			sdb.stopStats = reportPoolStats(de, cfg.Metrics, cfg.PoolStatsInterval)
+		}
+	}
+	return sdb, nil
+}
+
+// DryRun return a copy of the db which records the statements instead of executing them.
+// The copy shares the builder and options with db except that the slow statements are not explained,
+// the captured statements and the script can be read from the returned executor.
+func DryRun(db DB) (DB, *query.DryRunExecutor) {
+	e := query.NewDryRunExecutor(db.Builder())
+	opts := *db.Options()
+	opts.SlowQueryExplain = false
 	return DB{
-		query.NewQuery(b, db, cfg),
-		db,
-	}, nil
+		Query: query.NewQuery(db.Builder(), e, &opts),
+		sqlDB: e.DB,
+	}, e
 }
@@ -0,0 +1,83 @@
+package sealtest
+
+import (
+	"database/sql/driver"
+
+	"github.com/rumis/seal/internal/fakedriver"
+)
+
+// Rows is the result set returned by an expected query
+type Rows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+// NewRows generate an empty result set with the columns
+func NewRows(cols ...string) *Rows {
+	return &Rows{cols: cols}
+}
+
+// AddRow appends a row, the count of the values must be the same as the columns
+func (r *Rows) AddRow(vals ...interface{}) *Rows {
+	row := make([]driver.Value, len(vals))
+	for i, v := range vals {
+		row[i] = v
+	}
+	r.rows = append(r.rows, row)
+	return r
+}
+
+// handler checks the calls of the connections against the expectations of the mock
+type handler struct {
+	m *Mock
+}
+
+func (h handler) Begin() error {
+	return h.matchTx("begin")
+}
+
+func (h handler) Commit() error {
+	return h.matchTx("commit")
+}
+
+func (h handler) Rollback() error {
+	return h.matchTx("rollback")
+}
+
+// matchTx matches the transaction call and return the expected error
+func (h handler) matchTx(kind string) error {
+	e, err := h.m.match(kind, "", nil)
+	if err != nil {
+		return err
+	}
+	return e.(*ExpectedTx).err
+}
+
+// Exec matches the execution and return the expected result
+func (h handler) Exec(query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := h.m.match("exec", query, args)
+	if err != nil {
+		return nil, err
+	}
+	ee := e.(*ExpectedExec)
+	if ee.err != nil {
+		return nil, ee.err
+	}
+	return fakedriver.NewResult(ee.lastInsertId, ee.rowsAffected), nil
+}
+
+// Query matches the query and return the expected rows
+func (h handler) Query(query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := h.m.match("query", query, args)
+	if err != nil {
+		return nil, err
+	}
+	eq := e.(*ExpectedQuery)
+	if eq.err != nil {
+		return nil, eq.err
+	}
+	if eq.rows == nil {
+		return fakedriver.NewRows(nil, nil), nil
+	}
+	return fakedriver.NewRows(eq.rows.cols, eq.rows.rows), nil
+}
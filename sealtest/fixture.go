@@ -0,0 +1,128 @@
+package sealtest
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/rumis/seal/query"
+	"gopkg.in/yaml.v3"
+)
+
+// FixtureNameKey is the key of the row name in the fixture files, it is not inserted
+const FixtureNameKey = "_name"
+
+// Inserter generates the insert queries, eg. seal.DB and *seal.Tx
+type Inserter interface {
+	Insert(table string) *query.InsertQuery
+}
+
+// Fixtures keeps the ids of the named rows
+type Fixtures struct {
+	ids map[string]int64
+}
+
+// ID return the id of the named row, 0 if the name is not found
+func (f *Fixtures) ID(name string) int64 {
+	return f.ids[name]
+}
+
+// LoadFixtures inserts the rows in the YAML or JSON files in order, the tables of a file are inserted in the order they appear.
+// Each file maps table names to lists of rows:
+//
+//	class:
+//	  - _name: class1
+//	    name: grade one
+//	user:
+//	  - name: murong
+//	    class_id: "@class1"
+//
+// A row named by _name can be referenced by the later rows as "@name", which is replaced with its id.
+// The id is the "id" column of the row if set, otherwise the last insert id. Use "@@" to write a literal "@".
+func LoadFixtures(ctx context.Context, db Inserter, files ...string) (*Fixtures, error) {
+	f := &Fixtures{ids: make(map[string]int64)}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.load(ctx, db, data); err != nil {
+			return nil, fmt.Errorf("sealtest: load fixtures %s: %w", file, err)
+		}
+	}
+	return f, nil
+}
+
+// load inserts the rows of a fixture file
+func (f *Fixtures) load(ctx context.Context, db Inserter, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a map of tables", root.Line)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		table := root.Content[i].Value
+		rows := make([]map[string]interface{}, 0)
+		if err := root.Content[i+1].Decode(&rows); err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+		for n, row := range rows {
+			if err := f.insert(ctx, db, table, row); err != nil {
+				return fmt.Errorf("table %s row %d: %w", table, n, err)
+			}
+		}
+	}
+	return nil
+}
+
+// insert resolves the references of the row and inserts it
+func (f *Fixtures) insert(ctx context.Context, db Inserter, table string, row map[string]interface{}) error {
+	var name string
+	if v, ok := row[FixtureNameKey]; ok {
+		name = fmt.Sprint(v)
+		delete(row, FixtureNameKey)
+		if _, ok := f.ids[name]; ok {
+			return fmt.Errorf("duplicate name %s", name)
+		}
+	}
+	for col, v := range row {
+		s, ok := v.(string)
+		if !ok || !strings.HasPrefix(s, "@") {
+			continue
+		}
+		if strings.HasPrefix(s, "@@") {
+			row[col] = s[1:]
+			continue
+		}
+		id, ok := f.ids[s[1:]]
+		if !ok {
+			return fmt.Errorf("column %s references unknown row %s", col, s[1:])
+		}
+		row[col] = id
+	}
+
+	var id int64
+	if err := db.Insert(table).Values([]map[string]interface{}{row}).Exec(ctx, &id); err != nil {
+		return err
+	}
+	if name == "" {
+		return nil
+	}
+	if v, ok := row["id"]; ok {
+		switch n := v.(type) {
+		case int:
+			id = int64(n)
+		case int64:
+			id = n
+		}
+	}
+	f.ids[name] = id
+	return nil
+}
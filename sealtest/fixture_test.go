@@ -0,0 +1,74 @@
+package sealtest_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/rumis/seal"
+	"github.com/rumis/seal/sealtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func openSchool(t *testing.T) seal.DB {
+	db, err := seal.Open("sqlite3", filepath.Join(t.TempDir(), "school.db3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+	for _, ddl := range []string{
+		"CREATE TABLE class (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(64))",
+		"CREATE TABLE user (id INTEGER PRIMARY KEY AUTOINCREMENT, name VARCHAR(64), age INTEGER, class_id INTEGER)",
+	} {
+		if _, err := db.ExecContext(ctx, ddl).RowsAffected(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+type student struct {
+	Name  string `seal:"name"`
+	Age   int    `seal:"age"`
+	Class int64  `seal:"class_id"`
+}
+
+func TestFixtures(t *testing.T) {
+	ctx := context.Background()
+	db := openSchool(t)
+
+	t.Run("load", func(t *testing.T) {
+		tx := sealtest.NewTxDB(t, db)
+		f, err := sealtest.LoadFixtures(ctx, tx, "testdata/fixtures/school.yml", "testdata/fixtures/more.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, int64(20), f.ID("grade2"))
+		assert.NotZero(t, f.ID("grade1"))
+		assert.NotZero(t, f.ID("zhang"))
+
+		users := make([]student, 0)
+		err = tx.Select("name", "age", "class_id").From("user").OrderBy("id").Query(ctx).AllStruct(&users)
+		assert.Nil(t, err)
+		assert.Equal(t, []student{
+			{Name: "murong", Age: 13, Class: f.ID("grade1")},
+			{Name: "@liu", Age: 14, Class: 20},
+			{Name: "zhang", Age: 15, Class: f.ID("grade1")},
+		}, users)
+	})
+
+	// the changes are rolled back
+	var cnt int64
+	err := db.Count("id").From("user").Query(ctx).Agg(&cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), cnt)
+}
+
+func TestFixturesUnknownReference(t *testing.T) {
+	ctx := context.Background()
+	tx := sealtest.NewTxDB(t, openSchool(t))
+	_, err := sealtest.LoadFixtures(ctx, tx, "testdata/fixtures/more.json")
+	assert.EqualError(t, err, "sealtest: load fixtures testdata/fixtures/more.json: table user row 0: column class_id references unknown row grade1")
+}
@@ -0,0 +1,260 @@
+package sealtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rumis/seal/query"
+)
+
+// UpdateFlag is the flag which makes the golden recorders write the golden files instead of comparing with them,
+// e.g. go test ./... -update. The env SEALTEST_UPDATE=1 does the same.
+const UpdateFlag = "update"
+
+func init() {
+	// the flag may be registered by another package which is initialized before
+	if flag.Lookup(UpdateFlag) == nil {
+		flag.Bool(UpdateFlag, false, "update the golden files of sealtest")
+	}
+}
+
+// updateGolden reports whether the golden files are written, the flag is read when the test finishes
+func updateGolden() bool {
+	if os.Getenv("SEALTEST_UPDATE") != "" {
+		return true
+	}
+	f := flag.Lookup(UpdateFlag)
+	if f == nil {
+		return false
+	}
+	update, _ := strconv.ParseBool(f.Value.String())
+	return update
+}
+
+// GoldenDir is the directory of the golden files
+var GoldenDir = filepath.Join("testdata", "golden")
+
+// Golden wraps an executor and records every statement and its args.
+// When the test finishes, the statements are written to the golden file of the test if -update is set,
+// otherwise they are compared with the golden file and the test fails with a diff if they differ.
+// Transactions are available if the wrapped executor can begin them like *sql.DB, their statements are recorded too.
+type Golden struct {
+	e    query.Executor
+	t    testing.TB
+	path string
+
+	mu    sync.Mutex
+	stmts []string
+}
+
+var _ query.Executor = &Golden{}
+
+// NewGolden generate the recorder of the test, the golden file is GoldenDir/<test name>.golden
+func NewGolden(t testing.TB, e query.Executor) *Golden {
+	g := &Golden{
+		e:    e,
+		t:    t,
+		path: filepath.Join(GoldenDir, goldenName(t.Name())+".golden"),
+	}
+	t.Cleanup(g.check)
+	return g
+}
+
+// ExecContext records and executes the statement
+func (g *Golden) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	g.record("exec", query, args)
+	return g.e.ExecContext(ctx, query, args...)
+}
+
+// QueryContext records and queries the statement
+func (g *Golden) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	g.record("query", query, args)
+	return g.e.QueryContext(ctx, query, args...)
+}
+
+// PrepareContext records and prepares the statement
+func (g *Golden) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	g.record("prepare", query, nil)
+	return g.e.PrepareContext(ctx, query)
+}
+
+// BeginTx starts a transaction on the wrapped executor, the statements of the transaction are recorded by the executor of WrapTx
+func (g *Golden) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	b, ok := g.e.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return nil, errors.New("sealtest: the executor of golden does not support transactions")
+	}
+	return b.BeginTx(ctx, opts)
+}
+
+// WrapTx return the executor which records and runs the statements in the transaction
+func (g *Golden) WrapTx(tx *sql.Tx) query.Executor {
+	return &goldenTx{g: g, tx: tx}
+}
+
+// Stats return the stats of the wrapped executor, it is empty if the executor has no connection pool
+func (g *Golden) Stats() sql.DBStats {
+	if s, ok := g.e.(interface{ Stats() sql.DBStats }); ok {
+		return s.Stats()
+	}
+	return sql.DBStats{}
+}
+
+// Close closes the wrapped executor if it can be closed
+func (g *Golden) Close() error {
+	if c, ok := g.e.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// goldenTx records the statements executed in a transaction
+type goldenTx struct {
+	g  *Golden
+	tx *sql.Tx
+}
+
+// ExecContext records and executes the statement
+func (t *goldenTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	t.g.record("exec", query, args)
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+// QueryContext records and queries the statement
+func (t *goldenTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	t.g.record("query", query, args)
+	return t.tx.QueryContext(ctx, query, args...)
+}
+
+// PrepareContext records and prepares the statement
+func (t *goldenTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	t.g.record("prepare", query, nil)
+	return t.tx.PrepareContext(ctx, query)
+}
+
+// StmtContext rebinds the cached statement to the transaction
+func (t *goldenTx) StmtContext(ctx context.Context, stmt *sql.Stmt) *sql.Stmt {
+	return t.tx.StmtContext(ctx, stmt)
+}
+
+// Content return the recorded statements in the format of the golden file
+func (g *Golden) Content() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return strings.Join(g.stmts, "")
+}
+
+// record appends the statement
+func (g *Golden) record(kind string, query string, args []interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	s := fmt.Sprintf("-- %d %s\n%s\n", len(g.stmts)+1, kind, query)
+	if kind != "prepare" {
+		s += "-- args: " + formatArgs(args) + "\n"
+	}
+	g.stmts = append(g.stmts, s)
+}
+
+// check writes or compares the golden file
+func (g *Golden) check() {
+	got := g.Content()
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(g.path), 0755); err != nil {
+			g.t.Fatalf("sealtest: create golden dir: %v", err)
+		}
+		if err := ioutil.WriteFile(g.path, []byte(got), 0644); err != nil {
+			g.t.Fatalf("sealtest: write golden file: %v", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(g.path)
+	if err != nil {
+		g.t.Errorf("sealtest: read golden file: %v, run the test with -update to create it", err)
+		return
+	}
+	if string(want) != got {
+		g.t.Errorf("sealtest: statements differ from %s (-want +got):\n%s", g.path, Diff(string(want), got))
+	}
+}
+
+// goldenNameRe matches the chars which are not safe in file names
+var goldenNameRe = regexp.MustCompile(`[^\w.-]+`)
+
+// goldenName converts the test name to a file name, subtests are kept in sub directories
+func goldenName(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = goldenNameRe.ReplaceAllString(p, "_")
+	}
+	return filepath.Join(parts...)
+}
+
+// formatArgs formats the args in a stable and readable way
+func formatArgs(args []interface{}) string {
+	vals := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			vals[i] = fmt.Sprintf("%q", v)
+		case []byte:
+			vals[i] = fmt.Sprintf("x'%x'", v)
+		case nil:
+			vals[i] = "NULL"
+		default:
+			vals[i] = fmt.Sprintf("%v", v)
+		}
+	}
+	return "[" + strings.Join(vals, ", ") + "]"
+}
+
+// Diff return the line diff of want and got, the removed lines are prefixed by "-" and the added lines by "+"
+func Diff(want string, got string) string {
+	a, b := strings.Split(strings.TrimSuffix(want, "\n"), "\n"), strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var s strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			s.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			s.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			s.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,125 @@
+package sealtest_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/rumis/seal"
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/query"
+	"github.com/rumis/seal/sealtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGolden(t *testing.T) {
+	ctx := context.Background()
+	b := builder.NewMysqlBuilder()
+	g := sealtest.NewGolden(t, query.NewDryRunExecutor(b))
+	db, _ := seal.OpenWithExecutor(g, b)
+
+	var id, cnt int64
+	assert.Nil(t, db.Insert("user").Value(map[string]interface{}{"name": "murong", "age": 13}).Exec(ctx, &id))
+	assert.Nil(t, db.Update("user").Value(map[string]interface{}{"name": "liu", "age": 14}).Where(seal.Eq("id", id)).Exec(ctx, &cnt))
+	_, err := db.Select("id", "name").From("user").Where(seal.In("id", 1, 2)).Query(ctx).AllMap()
+	assert.Nil(t, err)
+}
+
+func TestGoldenTx(t *testing.T) {
+	ctx := context.Background()
+	b := builder.NewMysqlBuilder()
+	g := sealtest.NewGolden(t, query.NewDryRunExecutor(b))
+	db, _ := seal.OpenWithExecutor(g, b)
+
+	err := db.Transaction(ctx, func(tx *seal.Tx) error {
+		var id int64
+		if err := tx.Insert("user").Value(map[string]interface{}{"name": "murong"}).Exec(ctx, &id); err != nil {
+			return err
+		}
+		_, err := tx.Select("id").From("user").Where(seal.Eq("name", "murong")).Query(ctx).AllMap()
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, g.Content(), "INSERT INTO user (name) VALUES (?)\n")
+	assert.Contains(t, g.Content(), "SELECT id FROM user WHERE name=?\n")
+}
+
+// recordTB records the errors and cleanups of the test
+type recordTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordTB) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recordTB) Name() string {
+	return "TestGolden"
+}
+
+// setUpdate sets the -update flag until the test finishes
+func setUpdate(t *testing.T, update bool) {
+	old := flag.Lookup(sealtest.UpdateFlag).Value.String()
+	assert.Nil(t, flag.Set(sealtest.UpdateFlag, fmt.Sprint(update)))
+	t.Cleanup(func() {
+		flag.Set(sealtest.UpdateFlag, old)
+	})
+}
+
+func TestGoldenMismatch(t *testing.T) {
+	ctx := context.Background()
+	b := builder.NewMysqlBuilder()
+	// the golden file is compared even if the tests run with -update
+	setUpdate(t, false)
+	tb := &recordTB{TB: t}
+	db, _ := seal.OpenWithExecutor(sealtest.NewGolden(tb, query.NewDryRunExecutor(b)), b)
+
+	var id int64
+	assert.Nil(t, db.Insert("user").Value(map[string]interface{}{"name": "murong", "age": 12}).Exec(ctx, &id))
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	if assert.Len(t, tb.errors, 1) {
+		assert.Contains(t, tb.errors[0], "statements differ from testdata/golden/TestGolden.golden (-want +got):\n")
+		assert.Contains(t, tb.errors[0], "\n  INSERT INTO user (age, name) VALUES (?,?)\n- -- args: [13, \"murong\"]\n")
+		assert.Contains(t, tb.errors[0], "\n+ -- args: [12, \"murong\"]\n")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	assert.Equal(t, "  a\n- b\n+ x\n  c\n+ d\n", sealtest.Diff("a\nb\nc", "a\nx\nc\nd"))
+}
+
+func TestGoldenUpdate(t *testing.T) {
+	ctx := context.Background()
+	b := builder.NewMysqlBuilder()
+
+	dir := sealtest.GoldenDir
+	sealtest.GoldenDir = t.TempDir()
+	defer func() {
+		sealtest.GoldenDir = dir
+	}()
+	// the -update flag is read when the test finishes
+	tb := &recordTB{TB: t}
+	db, _ := seal.OpenWithExecutor(sealtest.NewGolden(tb, query.NewDryRunExecutor(b)), b)
+	setUpdate(t, true)
+
+	var id int64
+	assert.Nil(t, db.Insert("user").Value(map[string]interface{}{"name": "murong"}).Exec(ctx, &id))
+	for _, fn := range tb.cleanups {
+		fn()
+	}
+	assert.Empty(t, tb.errors)
+	content, err := ioutil.ReadFile(filepath.Join(sealtest.GoldenDir, "TestGolden.golden"))
+	assert.Nil(t, err)
+	assert.Equal(t, "-- 1 exec\nINSERT INTO user (name) VALUES (?)\n-- args: [\"murong\"]\n", string(content))
+}
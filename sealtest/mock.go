@@ -0,0 +1,354 @@
+// Package sealtest provides the helpers to test the code built on seal without a real database.
+//
+//	mock := sealtest.NewMock()
+//	db, _ := seal.OpenWithExecutor(mock, builder.NewMysqlBuilder())
+//	mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name FROM user WHERE id=?")).
+//		WithArgs(1).
+//		WillReturnRows(sealtest.NewRows("id", "name").AddRow(1, "murong"))
+//	... // run the code under test with db
+//	if err := mock.ExpectationsWereMet(); err != nil {
+//		t.Error(err)
+//	}
+package sealtest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rumis/seal/internal/fakedriver"
+)
+
+// Mock is an executor which checks the statements against the expectations and returns the expected results.
+// It is backed by an in-memory driver, so it can also be used to begin transactions and prepare statements.
+type Mock struct {
+	*sql.DB
+
+	mu        sync.Mutex
+	expected  []expectation
+	unordered bool
+}
+
+// NewMock generate a mock which matches the expectations in order
+func NewMock() *Mock {
+	m := &Mock{}
+	m.DB = fakedriver.Open(handler{m: m})
+	return m
+}
+
+// MatchUnordered allows the statements to match the expectations in any order
+func (m *Mock) MatchUnordered() *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unordered = true
+	return m
+}
+
+// ExpectQuery expects a query whose sql matches the regular expression pattern,
+// use regexp.QuoteMeta to match the sql literally
+func (m *Mock) ExpectQuery(pattern string) *ExpectedQuery {
+	e := &ExpectedQuery{expectedStmt: newExpectedStmt("query", pattern)}
+	m.expect(e)
+	return e
+}
+
+// ExpectExec expects an execution whose sql matches the regular expression pattern,
+// use regexp.QuoteMeta to match the sql literally
+func (m *Mock) ExpectExec(pattern string) *ExpectedExec {
+	e := &ExpectedExec{expectedStmt: newExpectedStmt("exec", pattern)}
+	m.expect(e)
+	return e
+}
+
+// ExpectBegin expects a transaction to begin
+func (m *Mock) ExpectBegin() *ExpectedTx {
+	e := &ExpectedTx{kind: "begin"}
+	m.expect(e)
+	return e
+}
+
+// ExpectCommit expects a transaction to commit
+func (m *Mock) ExpectCommit() *ExpectedTx {
+	e := &ExpectedTx{kind: "commit"}
+	m.expect(e)
+	return e
+}
+
+// ExpectRollback expects a transaction to roll back
+func (m *Mock) ExpectRollback() *ExpectedTx {
+	e := &ExpectedTx{kind: "rollback"}
+	m.expect(e)
+	return e
+}
+
+// ExpectationsWereMet return an error listing the expectations which are not fulfilled
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var missing []string
+	for _, e := range m.expected {
+		if !e.fulfilled() {
+			missing = append(missing, "  - "+e.String())
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("sealtest: %d expectations were not met:\n%s", len(missing), strings.Join(missing, "\n"))
+}
+
+// expect appends the expectation
+func (m *Mock) expect(e expectation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expected = append(m.expected, e)
+}
+
+// match finds the expectation of the call and marks it fulfilled
+func (m *Mock) match(kind string, query string, args []driver.NamedValue) (expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	call := describeCall(kind, query, args)
+	for _, e := range m.expected {
+		if e.fulfilled() {
+			continue
+		}
+		if err := e.match(kind, query, args); err != nil {
+			if m.unordered {
+				continue
+			}
+			return nil, fmt.Errorf("sealtest: unexpected %s, next expectation is %s: %v", call, e, err)
+		}
+		e.fulfill()
+		return e, nil
+	}
+	return nil, fmt.Errorf("sealtest: unexpected %s, no expectation matched", call)
+}
+
+// describeCall formats the call for the error messages
+func describeCall(kind string, query string, args []driver.NamedValue) string {
+	if kind != "query" && kind != "exec" {
+		return kind
+	}
+	vals := make([]interface{}, len(args))
+	for i, arg := range args {
+		vals[i] = arg.Value
+	}
+	return fmt.Sprintf("%s %q with args %v", kind, query, vals)
+}
+
+// expectation is an expected call of the mock
+type expectation interface {
+	fmt.Stringer
+	match(kind string, query string, args []driver.NamedValue) error
+	fulfilled() bool
+	fulfill()
+}
+
+// expectedStmt is the expectation of a query or an execution
+type expectedStmt struct {
+	kind      string
+	pattern   string
+	re        *regexp.Regexp
+	args      []interface{}
+	checkArgs bool
+	err       error
+	done      bool
+}
+
+func newExpectedStmt(kind string, pattern string) expectedStmt {
+	return expectedStmt{kind: kind, pattern: pattern, re: regexp.MustCompile(pattern)}
+}
+
+func (e *expectedStmt) String() string {
+	if e.checkArgs {
+		return fmt.Sprintf("%s matching %q with args %v", e.kind, e.pattern, e.args)
+	}
+	return fmt.Sprintf("%s matching %q", e.kind, e.pattern)
+}
+
+func (e *expectedStmt) fulfilled() bool {
+	return e.done
+}
+
+func (e *expectedStmt) fulfill() {
+	e.done = true
+}
+
+func (e *expectedStmt) match(kind string, query string, args []driver.NamedValue) error {
+	if kind != e.kind {
+		return fmt.Errorf("got %s", kind)
+	}
+	if !e.re.MatchString(query) {
+		return fmt.Errorf("sql %q does not match %q", query, e.pattern)
+	}
+	if !e.checkArgs {
+		return nil
+	}
+	if len(args) != len(e.args) {
+		return fmt.Errorf("got %d args, expected %d", len(args), len(e.args))
+	}
+	for i, arg := range args {
+		if !matchArg(e.args[i], arg.Value) {
+			return fmt.Errorf("arg %d is %#v, expected %#v", i, arg.Value, e.args[i])
+		}
+	}
+	return nil
+}
+
+// Argument matches an arg of the statement
+type Argument interface {
+	Match(v interface{}) bool
+}
+
+// anyArg matches any arg
+type anyArg struct{}
+
+func (a anyArg) Match(v interface{}) bool {
+	return true
+}
+
+// AnyArg return an Argument which matches any arg
+func AnyArg() Argument {
+	return anyArg{}
+}
+
+// matchArg reports whether the actual arg matches the expected one, numbers are compared by value
+func matchArg(expected interface{}, actual interface{}) bool {
+	if a, ok := expected.(Argument); ok {
+		return a.Match(actual)
+	}
+	if v, ok := expected.(driver.Valuer); ok {
+		if ev, err := v.Value(); err == nil {
+			expected = ev
+		}
+	}
+	if reflect.DeepEqual(expected, actual) {
+		return true
+	}
+	ev, av := reflect.ValueOf(expected), reflect.ValueOf(actual)
+	switch {
+	case isInt(ev) && isInt(av):
+		return toInt(ev) == toInt(av)
+	case isNumber(ev) && isNumber(av):
+		return toFloat(ev) == toFloat(av)
+	}
+	return false
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isNumber(v reflect.Value) bool {
+	return isInt(v) || v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64
+}
+
+func toInt(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return float64(toInt(v))
+}
+
+// ExpectedQuery is the expectation of a query
+type ExpectedQuery struct {
+	expectedStmt
+	rows *Rows
+}
+
+// WithArgs expects the args of the query, an Argument can be used to match the arg
+func (e *ExpectedQuery) WithArgs(args ...interface{}) *ExpectedQuery {
+	e.args = args
+	e.checkArgs = true
+	return e
+}
+
+// WillReturnRows set the rows returned by the query
+func (e *ExpectedQuery) WillReturnRows(rows *Rows) *ExpectedQuery {
+	e.rows = rows
+	return e
+}
+
+// WillReturnError set the error returned by the query
+func (e *ExpectedQuery) WillReturnError(err error) *ExpectedQuery {
+	e.err = err
+	return e
+}
+
+// ExpectedExec is the expectation of an execution
+type ExpectedExec struct {
+	expectedStmt
+	lastInsertId int64
+	rowsAffected int64
+}
+
+// WithArgs expects the args of the execution, an Argument can be used to match the arg
+func (e *ExpectedExec) WithArgs(args ...interface{}) *ExpectedExec {
+	e.args = args
+	e.checkArgs = true
+	return e
+}
+
+// WillReturnResult set the result of the execution
+func (e *ExpectedExec) WillReturnResult(lastInsertId int64, rowsAffected int64) *ExpectedExec {
+	e.lastInsertId = lastInsertId
+	e.rowsAffected = rowsAffected
+	return e
+}
+
+// WillReturnError set the error returned by the execution
+func (e *ExpectedExec) WillReturnError(err error) *ExpectedExec {
+	e.err = err
+	return e
+}
+
+// ExpectedTx is the expectation of beginning, committing or rolling back a transaction
+type ExpectedTx struct {
+	kind string
+	err  error
+	done bool
+}
+
+// WillReturnError set the error returned by the call
+func (e *ExpectedTx) WillReturnError(err error) *ExpectedTx {
+	e.err = err
+	return e
+}
+
+func (e *ExpectedTx) String() string {
+	return e.kind
+}
+
+func (e *ExpectedTx) match(kind string, query string, args []driver.NamedValue) error {
+	if kind != e.kind {
+		return fmt.Errorf("got %s", kind)
+	}
+	return nil
+}
+
+func (e *ExpectedTx) fulfilled() bool {
+	return e.done
+}
+
+func (e *ExpectedTx) fulfill() {
+	e.done = true
+}
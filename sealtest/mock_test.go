@@ -0,0 +1,95 @@
+package sealtest_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/rumis/seal"
+	"github.com/rumis/seal/builder"
+	"github.com/rumis/seal/query"
+	"github.com/rumis/seal/sealtest"
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	ID   int64  `seal:"id"`
+	Name string `seal:"name"`
+}
+
+func TestMock(t *testing.T) {
+	ctx := context.Background()
+	mock := sealtest.NewMock()
+	db, err := seal.OpenWithExecutor(mock, builder.NewMysqlBuilder())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id,name FROM user WHERE id=?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sealtest.NewRows("id", "name").AddRow(1, "murong"))
+	mock.ExpectBegin()
+	mock.ExpectExec("^INSERT INTO user").WithArgs("liu").WillReturnResult(7, 1)
+	mock.ExpectExec("^UPDATE user").WithArgs(sealtest.AnyArg(), 7).WillReturnError(errors.New("boom"))
+	mock.ExpectRollback()
+
+	users := make([]user, 0)
+	err = db.Select("id", "name").From("user").Where(seal.Eq("id", 1)).Query(ctx).AllStruct(&users)
+	assert.Nil(t, err)
+	assert.Equal(t, []user{{ID: 1, Name: "murong"}}, users)
+
+	err = db.Transaction(ctx, func(tx *seal.Tx) error {
+		var id, cnt int64
+		if err := tx.Insert("user").Value(map[string]interface{}{"name": "liu"}).Exec(ctx, &id); err != nil {
+			return err
+		}
+		assert.Equal(t, int64(7), id)
+		return tx.Update("user").Value(map[string]interface{}{"name": "x"}).Where(seal.Eq("id", id)).Exec(ctx, &cnt)
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
+
+func TestMockOrder(t *testing.T) {
+	ctx := context.Background()
+	mock := sealtest.NewMock()
+	db, _ := seal.OpenWithExecutor(mock, builder.NewMysqlBuilder())
+
+	mock.ExpectExec("^DELETE FROM a")
+	mock.ExpectExec("^DELETE FROM b")
+
+	var cnt int64
+	err := db.Delete("b").Where(seal.Eq("id", 1)).Exec(ctx, &cnt)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unexpected exec "DELETE FROM b WHERE id=?" with args [1], next expectation is exec matching "^DELETE FROM a"`)
+
+	mock = sealtest.NewMock().MatchUnordered()
+	db, _ = seal.OpenWithExecutor(mock, builder.NewMysqlBuilder())
+	mock.ExpectExec("^DELETE FROM a").WithArgs(1)
+	mock.ExpectExec("^DELETE FROM b").WithArgs(2)
+
+	assert.Nil(t, db.Delete("b").Where(seal.Eq("id", 2)).Exec(ctx, &cnt))
+	err = mock.ExpectationsWereMet()
+	assert.EqualError(t, err, "sealtest: 1 expectations were not met:\n  - exec matching \"^DELETE FROM a\" with args [1]")
+	assert.Nil(t, db.Delete("a").Where(seal.Eq("id", 1)).Exec(ctx, &cnt))
+	assert.Nil(t, mock.ExpectationsWereMet())
+
+	// nothing expected
+	_, err = db.Select("id").From("a").Query(ctx).AllMap()
+	assert.EqualError(t, err, `sealtest: unexpected query "SELECT id FROM a" with args [], no expectation matched`)
+}
+
+func TestOpenWithExecutorNoTx(t *testing.T) {
+	// the executor can not begin transactions
+	e := struct{ query.Executor }{sealtest.NewMock()}
+	db, err := seal.OpenWithExecutor(e, builder.NewMysqlBuilder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Begin()
+	assert.Error(t, err)
+	assert.Equal(t, sql.DBStats{}, db.Stats())
+	assert.Nil(t, db.Close())
+}
@@ -0,0 +1,26 @@
+package sealtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/rumis/seal"
+)
+
+// NewTxDB begins a transaction on db which is rolled back when the test finishes,
+// so the changes made by the test are never seen by the other tests.
+// Code under test can run nested transactions on it, they are turned into savepoints.
+func NewTxDB(t testing.TB, db seal.DB) *seal.Tx {
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("sealtest: begin transaction: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			t.Errorf("sealtest: rollback transaction: %v", err)
+		}
+	})
+	return tx
+}
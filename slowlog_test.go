@@ -0,0 +1,100 @@
+package seal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := make(chan options.SlowQuery, 10)
+	db, err := Open("sqlite3", dbfile,
+		options.WithSlowQueryLog(0, func(ctx context.Context, q options.SlowQuery) {
+			entries <- q
+		}),
+		options.WithSlowQueryExplain(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Select("id", "name").From("class").Where(Eq("name", "slow")).Query(ctx).AllMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, rows)
+
+	select {
+	case entry := <-entries:
+		assert.Equal(t, options.OpSelect, entry.Op)
+		assert.Equal(t, "class", entry.Table)
+		assert.Equal(t, "SELECT id,name FROM class WHERE name=?", entry.SQL)
+		assert.Equal(t, []interface{}{"slow"}, entry.Args)
+		assert.NoError(t, entry.ExplainErr)
+		if assert.NotEmpty(t, entry.Plan) {
+			assert.Contains(t, entry.Plan[0]["detail"], "SCAN")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow query not logged")
+	}
+
+	// the statements of dry run are not explained
+	dry, e := DryRun(db)
+	defer dry.Close()
+	_, err = dry.Select("id").From("class").Query(ctx).AllMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case entry := <-entries:
+		assert.Equal(t, "SELECT id FROM class", entry.SQL)
+		assert.Nil(t, entry.Plan)
+		assert.NoError(t, entry.ExplainErr)
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow query not logged")
+	}
+	assert.Equal(t, "SELECT id FROM class;\n", e.Script())
+	assert.True(t, db.Options().SlowQueryExplain)
+
+	// sampled out
+	sampled, err := Open("sqlite3", dbfile,
+		options.WithSlowQueryLog(0, func(ctx context.Context, q options.SlowQuery) {
+			entries <- q
+		}),
+		options.WithSlowQuerySampling(0.000000001),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sampled.Close()
+	var cnt int64
+	if err := sampled.Count("id").From("class").Query(ctx).Agg(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, entries, 0)
+
+	// over the threshold
+	fast, err := Open("sqlite3", dbfile,
+		options.WithSlowQueryLog(time.Hour, func(ctx context.Context, q options.SlowQuery) {
+			entries <- q
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fast.Close()
+	if err := fast.Count("id").From("class").Query(ctx).Agg(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, entries, 0)
+}
@@ -0,0 +1,102 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+type postModel struct {
+	ID        int64      `seal:"id"`
+	Title     string     `seal:"title"`
+	DeletedAt *time.Time `seal:"deleted_at,softdelete"`
+}
+
+func (postModel) TableName() string {
+	return "post"
+}
+
+func TestSoftDelete(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+	db, err := Open("sqlite3", dbfile,
+		options.WithSoftDelete("post", "deleted_at"),
+		options.WithTimestamps("post", "", "updated_at"),
+		options.WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE post (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		title      VARCHAR (64) NOT NULL,
+		updated_at DATETIME NULL,
+		deleted_at DATETIME NULL
+	)`).RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := []string{randString(8), randString(9), randString(10)}
+	for _, title := range titles {
+		var id int64
+		if err := db.Insert("post").Value(map[string]interface{}{"title": title}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// delete becomes an update of the deleted time and the updated time
+	now = now.Add(time.Hour)
+	var cnt int64
+	err = db.Delete("post").Where(Eq("title", titles[0])).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+	row, err := db.Select("deleted_at", "updated_at").From("post").Where(Eq("title", titles[0])).WithDeleted().Query(ctx).OneMap()
+	assert.Nil(t, err)
+	assert.Equal(t, now, row["deleted_at"])
+	assert.Equal(t, now, row["updated_at"])
+
+	// the deleted rows are not deleted again
+	deletedAt := now
+	now = now.Add(time.Hour)
+	err = db.Delete("post").Where(Eq("title", titles[0])).WithDeleted().Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), cnt)
+	row, err = db.Select("deleted_at").From("post").Where(Eq("title", titles[0])).WithDeleted().Query(ctx).OneMap()
+	assert.Nil(t, err)
+	assert.Equal(t, deletedAt, row["deleted_at"])
+
+	// the deleted rows are excluded
+	assert.Nil(t, db.Count("id").From("post").Query(ctx).Agg(&cnt))
+	assert.Equal(t, int64(2), cnt)
+	err = db.Update("post").Value(map[string]interface{}{"title": "x"}).Where(Eq("title", titles[0])).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), cnt)
+	assert.Nil(t, db.Count("id").From("post").OnlyDeleted().Query(ctx).Agg(&cnt))
+	assert.Equal(t, int64(1), cnt)
+
+	// purge the deleted rows
+	err = db.Delete("post").OnlyDeleted().HardDelete().All().Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+	assert.Nil(t, db.Count("id").From("post").WithDeleted().Query(ctx).Agg(&cnt))
+	assert.Equal(t, int64(2), cnt)
+
+	// model
+	var p postModel
+	assert.Nil(t, db.Model(&p).Find(ctx, 2))
+	assert.Equal(t, titles[1], p.Title)
+	assert.Nil(t, db.Model(&p).Delete(ctx))
+	assert.Equal(t, now, *p.DeletedAt)
+	err = db.Model(&postModel{}).Find(ctx, 2)
+	assert.True(t, errors.Is(err, ErrNoRows))
+}
@@ -0,0 +1,94 @@
+package sqlerr
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// error numbers of mysql
+const (
+	mysqlDupEntry         = 1062
+	mysqlDupEntryWithKey  = 1586
+	mysqlBadNull          = 1048
+	mysqlNoDefault        = 1364
+	mysqlRowIsReferenced  = 1451
+	mysqlNoReferencedRow  = 1452
+	mysqlRowIsReferenced2 = 1217
+	mysqlNoReferencedRow2 = 1216
+	mysqlLockWaitTimeout  = 1205
+	mysqlLockDeadlock     = 1213
+)
+
+var (
+	mysqlNumberRe  = regexp.MustCompile(`^Error (\d+)`)
+	mysqlDupKeyRe  = regexp.MustCompile(`for key '([^']*)'`)
+	mysqlColumnRe  = regexp.MustCompile(`^(?:Column|Field) '([^']*)'`)
+	mysqlForeignRe = regexp.MustCompile("\\(`([^`]*)`\\.`([^`]*)`, CONSTRAINT `([^`]*)` FOREIGN KEY \\(([^)]*)\\)")
+)
+
+// translateMysql recognizes the errors of go-sql-driver/mysql by the error number.
+// The number is read from the Number field of the driver error, or parsed from the message.
+func translateMysql(err error) *Error {
+	num, msg := mysqlNumber(err)
+	switch num {
+	case mysqlDupEntry, mysqlDupEntryWithKey:
+		e := &Error{Kind: ErrUniqueViolation}
+		if m := mysqlDupKeyRe.FindStringSubmatch(msg); m != nil {
+			// mysql 8 reports the key as table.key
+			e.Constraint = m[1]
+			if i := strings.LastIndexByte(m[1], '.'); i >= 0 {
+				e.Table, e.Constraint = m[1][:i], m[1][i+1:]
+			}
+		}
+		return e
+	case mysqlBadNull, mysqlNoDefault:
+		e := &Error{Kind: ErrNotNullViolation}
+		if m := mysqlColumnRe.FindStringSubmatch(msg); m != nil {
+			e.Column = m[1]
+		}
+		return e
+	case mysqlRowIsReferenced, mysqlNoReferencedRow, mysqlRowIsReferenced2, mysqlNoReferencedRow2:
+		e := &Error{Kind: ErrForeignKeyViolation}
+		if m := mysqlForeignRe.FindStringSubmatch(msg); m != nil {
+			e.Table, e.Constraint = m[2], m[3]
+			e.Column = strings.ReplaceAll(strings.ReplaceAll(m[4], "`", ""), ", ", ",")
+		}
+		return e
+	case mysqlLockWaitTimeout:
+		return &Error{Kind: ErrLockTimeout}
+	case mysqlLockDeadlock:
+		return &Error{Kind: ErrDeadlock}
+	}
+	return nil
+}
+
+// mysqlNumber return the error number and message of the mysql error, 0 if it is not a mysql error
+func mysqlNumber(err error) (int, string) {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		v := reflect.ValueOf(e)
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		num, msg := v.FieldByName("Number"), v.FieldByName("Message")
+		if num.IsValid() && num.Kind() == reflect.Uint16 && msg.IsValid() && msg.Kind() == reflect.String {
+			return int(num.Uint()), msg.String()
+		}
+	}
+	msg := err.Error()
+	m := mysqlNumberRe.FindStringSubmatch(msg)
+	if m == nil {
+		return 0, msg
+	}
+	num, _ := strconv.Atoi(m[1])
+	// skip "Error 1062: " or "Error 1062 (23000): "
+	if i := strings.Index(msg, ": "); i >= 0 {
+		msg = msg[i+2:]
+	}
+	return num, msg
+}
@@ -0,0 +1,108 @@
+// Package sqlerr normalizes the errors of the database drivers.
+// The translator of a driver is registered by the driver name, which is the one passed to seal.Open or set by options.WithDriver.
+// Translated errors match the sentinels by errors.Is and still wrap the original driver error.
+//
+//	err := db.Insert("user").Value(u).Exec(ctx)
+//	if errors.Is(err, sqlerr.ErrUniqueViolation) {
+//		var e *sqlerr.Error
+//		errors.As(err, &e)
+//		log.Println("duplicate", e.Constraint)
+//	}
+package sqlerr
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// the kinds of the normalized errors
+var (
+	ErrUniqueViolation     = errors.New("unique violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrNotNullViolation    = errors.New("not null violation")
+	ErrDeadlock            = errors.New("deadlock")
+	ErrLockTimeout         = errors.New("lock timeout")
+	// ErrNoRows is translated from sql.ErrNoRows, it is returned by Model.Find when the row is not found.
+	// Rows.OneMap and Rows.OneStruct do not return it, they return an empty result with nil error.
+	ErrNoRows = errors.New("no rows")
+)
+
+// Error is the normalized error of a driver error
+type Error struct {
+	// Kind is one of the sentinel errors
+	Kind error
+	// Driver is the name of the driver which reports the error
+	Driver string
+	// Constraint is the name of the violated constraint (index or foreign key), empty if the driver does not report it
+	Constraint string
+	// Table is the table of the violated constraint, empty if the driver does not report it
+	Table string
+	// Column is the column of the violated constraint, columns are separated by comma if there are more than one.
+	// It is empty if the driver does not report it
+	Column string
+	// Err is the original error
+	Err error
+}
+
+// Error return the message of the original error
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap return the original error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the kind of the error
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Translator normalizes the error of a driver, nil is returned if the error is not recognized
+type Translator func(err error) *Error
+
+var (
+	mu          sync.RWMutex
+	translators = map[string]Translator{
+		"mysql":   translateMysql,
+		"sqlite3": translateSqlite,
+	}
+)
+
+// Register set the translator of the driver name, the builtin translators of mysql and sqlite3 can be replaced
+func Register(driver string, t Translator) {
+	mu.Lock()
+	defer mu.Unlock()
+	translators[driver] = t
+}
+
+// Translate normalizes the error reported by the driver.
+// The error is returned as is if it is nil, already translated or not recognized.
+func Translate(driver string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Error{Kind: ErrNoRows, Driver: driver, Err: err}
+	}
+	mu.RLock()
+	t, ok := translators[driver]
+	mu.RUnlock()
+	if !ok {
+		return err
+	}
+	if e = t(err); e == nil {
+		return err
+	}
+	e.Driver = driver
+	if e.Err == nil {
+		e.Err = err
+	}
+	return e
+}
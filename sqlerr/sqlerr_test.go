@@ -0,0 +1,82 @@
+package sqlerr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mysqlError has the same fields as the error of go-sql-driver/mysql
+type mysqlError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *mysqlError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+func TestTranslateMysql(t *testing.T) {
+	err := Translate("mysql", fmt.Errorf("insert user: %w", &mysqlError{Number: 1062, Message: "Duplicate entry 'a@b.c' for key 'user.uk_email'"}))
+	assert.True(t, errors.Is(err, ErrUniqueViolation))
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "user", e.Table)
+	assert.Equal(t, "uk_email", e.Constraint)
+	var me *mysqlError
+	assert.True(t, errors.As(err, &me))
+
+	err = Translate("mysql", errors.New("Error 1452 (23000): Cannot add or update a child row: a foreign key constraint fails (`db`.`user`, CONSTRAINT `fk_class` FOREIGN KEY (`class_id`) REFERENCES `class` (`id`))"))
+	assert.True(t, errors.Is(err, ErrForeignKeyViolation))
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, Error{Kind: ErrForeignKeyViolation, Driver: "mysql", Constraint: "fk_class", Table: "user", Column: "class_id", Err: e.Err}, *e)
+
+	err = Translate("mysql", errors.New("Error 1048: Column 'name' cannot be null"))
+	assert.True(t, errors.Is(err, ErrNotNullViolation))
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "name", e.Column)
+
+	assert.True(t, errors.Is(Translate("mysql", &mysqlError{Number: 1213}), ErrDeadlock))
+	assert.True(t, errors.Is(Translate("mysql", &mysqlError{Number: 1205}), ErrLockTimeout))
+
+	// not recognized
+	orig := errors.New("Error 1146: Table 'db.x' doesn't exist")
+	assert.Equal(t, orig, Translate("mysql", orig))
+}
+
+func TestTranslateSqlite(t *testing.T) {
+	err := Translate("sqlite3", errors.New("UNIQUE constraint failed: user.first, user.last"))
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, ErrUniqueViolation, e.Kind)
+	assert.Equal(t, "user", e.Table)
+	assert.Equal(t, "first,last", e.Column)
+
+	assert.True(t, errors.Is(Translate("sqlite3", errors.New("NOT NULL constraint failed: user.name")), ErrNotNullViolation))
+	assert.True(t, errors.Is(Translate("sqlite3", errors.New("FOREIGN KEY constraint failed")), ErrForeignKeyViolation))
+	assert.True(t, errors.Is(Translate("sqlite3", errors.New("database is locked")), ErrLockTimeout))
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Nil(t, Translate("mysql", nil))
+
+	err := Translate("standard", sql.ErrNoRows)
+	assert.True(t, errors.Is(err, ErrNoRows))
+	assert.True(t, errors.Is(err, sql.ErrNoRows))
+
+	// translated only once
+	assert.Equal(t, err, Translate("sqlite3", err))
+
+	Register("custom", func(err error) *Error {
+		if err.Error() == "dup" {
+			return &Error{Kind: ErrUniqueViolation, Constraint: "pk"}
+		}
+		return nil
+	})
+	err = Translate("custom", errors.New("dup"))
+	assert.True(t, errors.Is(err, ErrUniqueViolation))
+	assert.EqualError(t, err, "dup")
+}
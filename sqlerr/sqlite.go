@@ -0,0 +1,46 @@
+package sqlerr
+
+import (
+	"strings"
+)
+
+// translateSqlite recognizes the messages of sqlite3, e.g. "UNIQUE constraint failed: user.email"
+func translateSqlite(err error) *Error {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed") || strings.Contains(msg, "PRIMARY KEY must be unique"):
+		e := &Error{Kind: ErrUniqueViolation}
+		e.Table, e.Column = sqliteColumns(msg)
+		return e
+	case strings.Contains(msg, "NOT NULL constraint failed"):
+		e := &Error{Kind: ErrNotNullViolation}
+		e.Table, e.Column = sqliteColumns(msg)
+		return e
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return &Error{Kind: ErrForeignKeyViolation}
+	case strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked"):
+		return &Error{Kind: ErrLockTimeout}
+	case strings.Contains(msg, "deadlock"):
+		return &Error{Kind: ErrDeadlock}
+	}
+	return nil
+}
+
+// sqliteColumns parses the table and columns after "constraint failed: ", e.g. "user.first, user.last"
+func sqliteColumns(msg string) (string, string) {
+	i := strings.Index(msg, "failed: ")
+	if i < 0 {
+		return "", ""
+	}
+	var table string
+	cols := make([]string, 0)
+	for _, part := range strings.Split(msg[i+len("failed: "):], ",") {
+		part = strings.TrimSpace(part)
+		if j := strings.LastIndexByte(part, '.'); j >= 0 {
+			table = part[:j]
+			part = part[j+1:]
+		}
+		cols = append(cols, part)
+	}
+	return table, strings.Join(cols, ",")
+}
@@ -9,6 +9,12 @@ func Eq(col string, val interface{}) expr.Expr {
 	return expr.Op(col, "=", val)
 }
 
+// Param generates a named placeholder which value is bound when the prepared query is executed.
+// For example, Eq("id", Param("uid")) generates: "id"=? and the value of "uid" is bound by PreparedQuery.Bind
+func Param(name string) expr.NamedParam {
+	return expr.Param(name)
+}
+
 // StaticEq generates a static equal expression which without params
 func StaticEq(col1 string, col2 string) expr.Expr {
 	return StaticOp(col1, "=", col2)
@@ -0,0 +1,141 @@
+package seal
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/rumis/seal/options"
+	"github.com/rumis/seal/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStmtCache(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile, options.WithStmtCache(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cache := db.StmtCache()
+	if cache == nil {
+		t.Fatal("statement cache not enabled")
+	}
+
+	var id int64
+	for i := 0; i < 3; i++ {
+		if err := db.Insert("class").Value(Class{Name: randString(8)}).Exec(ctx, &id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, 1, cache.Len())
+
+	classes := make([]ClassResult, 0)
+	err = db.Select("id", "name").From("class").Where(Op("id", ">", 0)).Query(ctx).AllStruct(&classes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, classes, 3)
+	assert.Equal(t, 2, cache.Len())
+
+	// the cached statements are rebound to the transaction
+	err = db.Transaction(ctx, func(tx *Tx) error {
+		if err := tx.Insert("class").Value(Class{Name: randString(8)}).Exec(ctx, &id); err != nil {
+			return err
+		}
+		var cnt int64
+		if err := tx.Count("id").From("class").Query(ctx).Agg(&cnt); err != nil {
+			return err
+		}
+		assert.Equal(t, int64(4), cnt)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, cache.Len())
+
+	var cnt int64
+	if err := db.Count("id").From("class").Query(ctx).Agg(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(4), cnt)
+}
+
+// prepareCounter counts the statements prepared through the db
+type prepareCounter struct {
+	*sql.DB
+	prepared map[string]int
+}
+
+func (p *prepareCounter) PrepareContext(ctx context.Context, s string) (*sql.Stmt, error) {
+	p.prepared[s]++
+	return p.DB.PrepareContext(ctx, s)
+}
+
+func TestStmtCacheEvict(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	e := &prepareCounter{DB: db, prepared: make(map[string]int)}
+	cache := query.NewStmtCache(e, 2)
+	sqlA, sqlB, sqlC := "SELECT COUNT(id) FROM class", "SELECT COUNT(id) FROM user", "SELECT COUNT(name) FROM class"
+	get := func(s string) (*sql.Stmt, func()) {
+		stmt, release, err := cache.Get(ctx, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stmt, release
+	}
+
+	_, release := get(sqlA)
+	release()
+	stmtB, releaseB := get(sqlB)
+	// A is used again, so B is the least recently used one
+	_, release = get(sqlA)
+	release()
+	_, release = get(sqlC)
+	release()
+	assert.Equal(t, 2, cache.Len())
+	assert.Equal(t, map[string]int{sqlA: 1, sqlB: 1, sqlC: 1}, e.prepared)
+
+	// A and C stay cached
+	_, release = get(sqlA)
+	release()
+	_, release = get(sqlC)
+	release()
+	assert.Equal(t, map[string]int{sqlA: 1, sqlB: 1, sqlC: 1}, e.prepared)
+
+	// the evicted statement is still usable until its last reference is released
+	var cnt int64
+	assert.Nil(t, stmtB.QueryRowContext(ctx).Scan(&cnt))
+	releaseB()
+	assert.EqualError(t, stmtB.QueryRowContext(ctx).Scan(&cnt), "sql: statement is closed")
+
+	// the evicted sql is prepared again
+	_, release = get(sqlB)
+	release()
+	assert.Equal(t, map[string]int{sqlA: 1, sqlB: 2, sqlC: 1}, e.prepared)
+
+	// the statements are closed when the cache is closed
+	stmtA, release := get(sqlA)
+	release()
+	cache.Close()
+	assert.Equal(t, 0, cache.Len())
+	assert.EqualError(t, stmtA.QueryRowContext(ctx).Scan(&cnt), "sql: statement is closed")
+}
@@ -0,0 +1,123 @@
+package seal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+type timedUserModel struct {
+	ID         int64     `seal:"id,pk,autoincr"`
+	Name       string    `seal:"name"`
+	CreateTime time.Time `seal:"create_time,autocreatetime"`
+}
+
+func (timedUserModel) TableName() string {
+	return "user"
+}
+
+func TestTimestamps(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+	db, err := Open("sqlite3", dbfile,
+		options.WithTimestamps("class", "", "name"),
+		options.WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// struct tag
+	type timedUser struct {
+		Name       string    `seal:"name"`
+		CreateTime time.Time `seal:"create_time,autocreatetime"`
+	}
+	var id int64
+	err = db.Insert("user").Value(timedUser{Name: randString(8)}).Exec(ctx, &id)
+	assert.Nil(t, err)
+	row, err := db.Select("create_time").From("user").Where(Eq("id", id)).Query(ctx).OneMap()
+	assert.Nil(t, err)
+	assert.Equal(t, now, row["create_time"])
+
+	// table option, the clock value is encoded by the EncodeHook
+	err = db.Insert("class").Value(map[string]interface{}{}).Exec(ctx, &id)
+	assert.Nil(t, err)
+	now = now.Add(time.Hour)
+	var cnt int64
+	err = db.Update("class").Value(map[string]interface{}{"id": id}).Where(Eq("id", id)).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	row, err = db.Select("name").From("class").Where(Eq("id", id)).Query(ctx).OneMap()
+	assert.Nil(t, err)
+	assert.Equal(t, "2021-10-01 09:00:00", row["name"])
+
+	// model, the created time is filled back and not changed on save
+	m := timedUserModel{Name: randString(8)}
+	assert.Nil(t, db.Model(&m).Create(ctx))
+	assert.Equal(t, now, m.CreateTime)
+	var found timedUserModel
+	assert.Nil(t, db.Model(&found).Find(ctx, m.ID))
+	assert.Equal(t, now, found.CreateTime)
+
+	now = now.Add(time.Hour)
+	m.CreateTime = time.Time{}
+	assert.Nil(t, db.Model(&m).Save(ctx))
+	assert.True(t, m.CreateTime.IsZero())
+	assert.Nil(t, db.Model(&found).Find(ctx, m.ID))
+	assert.Equal(t, now.Add(-time.Hour), found.CreateTime)
+}
+
+type stampedClassModel struct {
+	ID   int64     `seal:"id,pk,autoincr"`
+	Name time.Time `seal:"name"`
+}
+
+func (stampedClassModel) TableName() string {
+	return "class"
+}
+
+func TestTimestampsEncoder(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2021, 10, 1, 8, 0, 0, 0, time.UTC)
+	db, err := Open("sqlite3", dbfile,
+		options.WithTimestamps("class", "", "name"),
+		options.WithClock(func() time.Time { return now }),
+		options.WithEncodeHook(func(typ reflect.Type, data interface{}) (interface{}, error) {
+			if t, ok := data.(time.Time); ok {
+				return t.Format("20060102-150405"), nil
+			}
+			return data, nil
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// the model writes the time encoded by the custom encoder
+	m := stampedClassModel{}
+	assert.Nil(t, db.Model(&m).Create(ctx))
+	assert.Equal(t, now, m.Name)
+	row, err := db.Select("name").From("class").Where(Eq("id", m.ID)).Query(ctx).OneMap()
+	assert.Nil(t, err)
+	assert.Equal(t, "20211001-080000", row["name"])
+
+	now = now.Add(time.Hour)
+	assert.Nil(t, db.Model(&m).Save(ctx))
+	assert.Equal(t, now, m.Name)
+	row, err = db.Select("name").From("class").Where(Eq("id", m.ID)).Query(ctx).OneMap()
+	assert.Nil(t, err)
+	assert.Equal(t, "20211001-090000", row["name"])
+}
@@ -0,0 +1,55 @@
+package seal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rumis/seal/options"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracer(t *testing.T) {
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracer := options.NewRecordingTracer()
+	db, err := Open("sqlite3", dbfile, options.WithTracer(tracer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	var id int64
+	if err := db.Insert("class").Value(Class{Name: "trace"}).Exec(ctx, &id); err != nil {
+		t.Fatal(err)
+	}
+	err = db.Select("id").From("unknown_table").Query(ctx).OneStruct(&struct{}{})
+	assert.Error(t, err)
+	parent.End()
+
+	spans := tracer.Spans()
+	if !assert.Len(t, spans, 5) {
+		t.FailNow()
+	}
+	names := make([]string, 0, len(spans))
+	for _, span := range spans[1:] {
+		names = append(names, span.Name)
+		assert.Equal(t, spans[0].ID, span.ParentID)
+		assert.Equal(t, "sqlite3", span.Attributes[options.AttrDialect])
+		assert.False(t, span.EndTime.IsZero())
+	}
+	assert.Equal(t, []string{options.SpanBuild, options.SpanExec, options.SpanBuild, options.SpanExec}, names)
+
+	insert := spans[2]
+	assert.Equal(t, "insert", insert.Attributes[options.AttrOperation])
+	assert.Equal(t, "class", insert.Attributes[options.AttrTable])
+	assert.Equal(t, int64(1), insert.Attributes[options.AttrRowsAffected])
+	assert.NoError(t, insert.Err)
+
+	sel := spans[4]
+	assert.Equal(t, "select", sel.Attributes[options.AttrOperation])
+	assert.Equal(t, "unknown_table", sel.Attributes[options.AttrTable])
+	assert.Error(t, sel.Err)
+}
@@ -1,24 +1,140 @@
 package seal
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 
 	"github.com/rumis/seal/query"
+	"github.com/rumis/seal/sqlerr"
 )
 
+// TxHookFunc is called after the outcome of a transaction is known.
+type TxHookFunc func(ctx context.Context)
+
 // Tx enhances sql.Tx with additional querying methods.
 type Tx struct {
 	query.Query
 
-	tx *sql.Tx
+	tx  *sql.Tx
+	ctx context.Context
+
+	// parent and savepoint are set when the Tx represents a nested transaction
+	parent    *Tx
+	savepoint string
+	depth     int
+
+	onCommit   []TxHookFunc
+	onRollback []TxHookFunc
+}
+
+// OnCommit registers a func which will be called after the transaction is committed.
+// Funcs are called in the order of registration.
+// If the Tx is a nested transaction, the funcs are called when the outermost transaction is committed.
+func (t *Tx) OnCommit(fn TxHookFunc) {
+	t.onCommit = append(t.onCommit, fn)
+}
+
+// OnRollback registers a func which will be called after the transaction is rolled back.
+// Funcs are called in the order of registration.
+// If the Tx is a nested transaction, the funcs are called when either the savepoint or the outermost transaction is rolled back.
+func (t *Tx) OnRollback(fn TxHookFunc) {
+	t.onRollback = append(t.onRollback, fn)
 }
 
 // Commit commits the transaction.
+// If the Tx is a nested transaction, the savepoint is released.
 func (t *Tx) Commit() error {
-	return t.tx.Commit()
+	if t.parent != nil {
+		if err := t.exec(t.ctx, "RELEASE SAVEPOINT "+t.savepoint); err != nil {
+			return err
+		}
+		// the changes now belong to the parent transaction, so do the hooks
+		t.parent.onCommit = append(t.parent.onCommit, t.onCommit...)
+		t.parent.onRollback = append(t.parent.onRollback, t.onRollback...)
+		return nil
+	}
+	err := sqlerr.Translate(t.Driver(), t.tx.Commit())
+	if err != nil {
+		if !errors.Is(err, sql.ErrTxDone) {
+			// the transaction is aborted by the driver if commit failed
+			t.fire(t.onRollback)
+		}
+		return err
+	}
+	t.fire(t.onCommit)
+	return nil
 }
 
 // Rollback aborts the transaction.
+// If the Tx is a nested transaction, the changes are rolled back to the savepoint.
 func (t *Tx) Rollback() error {
-	return t.tx.Rollback()
+	if t.parent != nil {
+		if err := t.exec(t.ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint); err != nil {
+			return err
+		}
+		// the savepoint is still on the stack after rolled back to
+		if err := t.exec(t.ctx, "RELEASE SAVEPOINT "+t.savepoint); err != nil {
+			return err
+		}
+		t.fire(t.onRollback)
+		return nil
+	}
+	err := t.tx.Rollback()
+	if err != nil {
+		return err
+	}
+	t.fire(t.onRollback)
+	return nil
+}
+
+// Transaction runs fn in a nested transaction which is backed by a savepoint.
+// The savepoint is released if fn returns nil, otherwise the changes are rolled back to the savepoint.
+func (t *Tx) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	nested := &Tx{
+		Query:     t.Query,
+		tx:        t.tx,
+		ctx:       ctx,
+		parent:    t,
+		savepoint: fmt.Sprintf("seal_sp_%d", t.depth+1),
+		depth:     t.depth + 1,
+	}
+	if err := t.exec(ctx, "SAVEPOINT "+nested.savepoint); err != nil {
+		return err
+	}
+	return runTx(nested, fn)
+}
+
+// exec executes a statement on the transaction and only reports the error
+func (t *Tx) exec(ctx context.Context, stmt string) error {
+	_, err := t.ExecContext(ctx, stmt).RowsAffected()
+	return err
+}
+
+// fire calls the hooks in order and resets both hook lists, so each hook is called only once
+func (t *Tx) fire(hooks []TxHookFunc) {
+	t.onCommit = nil
+	t.onRollback = nil
+	for _, fn := range hooks {
+		fn(t.ctx)
+	}
+}
+
+// runTx calls fn and commits the transaction if fn succeeded, rollback otherwise.
+// The transaction is also rolled back if fn panics, and the panic is propagated.
+func runTx(tx *Tx, fn func(tx *Tx) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+	if err = fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,98 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxHooks(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// commit
+	events := make([]string, 0)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.OnCommit(func(ctx context.Context) { events = append(events, "commit-1") })
+	tx.OnCommit(func(ctx context.Context) { events = append(events, "commit-2") })
+	tx.OnRollback(func(ctx context.Context) { events = append(events, "rollback") })
+	var id int64
+	if err := tx.Insert("class").Value(Class{Name: "tx-commit"}).Exec(ctx, &id); err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, events)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"commit-1", "commit-2"}, events)
+
+	// rollback
+	events = events[:0]
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.OnCommit(func(ctx context.Context) { events = append(events, "commit") })
+	tx.OnRollback(func(ctx context.Context) { events = append(events, "rollback") })
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"rollback"}, events)
+
+	// managed transaction with nested savepoints
+	events = events[:0]
+	errAbort := errors.New("abort")
+	err = db.Transaction(ctx, func(tx *Tx) error {
+		tx.OnCommit(func(ctx context.Context) { events = append(events, "outer-commit") })
+		if err := tx.Transaction(ctx, func(tx *Tx) error {
+			tx.OnCommit(func(ctx context.Context) { events = append(events, "released-commit") })
+			return tx.Insert("class").Value(Class{Name: "tx-released"}).Exec(ctx, &id)
+		}); err != nil {
+			return err
+		}
+		err := tx.Transaction(ctx, func(tx *Tx) error {
+			tx.OnCommit(func(ctx context.Context) { events = append(events, "aborted-commit") })
+			tx.OnRollback(func(ctx context.Context) { events = append(events, "aborted-rollback") })
+			if err := tx.Insert("class").Value(Class{Name: "tx-aborted"}).Exec(ctx, &id); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"aborted-rollback", "outer-commit", "released-commit"}, events)
+
+	var cnt int64
+	err = db.Count("id").From("class").Where(In("name", "tx-commit", "tx-released", "tx-aborted")).Query(ctx).Agg(&cnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, int64(2), cnt)
+
+	// managed transaction rolled back
+	events = events[:0]
+	err = db.Transaction(ctx, func(tx *Tx) error {
+		tx.OnRollback(func(ctx context.Context) { events = append(events, "rollback") })
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	assert.Equal(t, []string{"rollback"}, events)
+}
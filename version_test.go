@@ -0,0 +1,77 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type articleModel struct {
+	ID      int64  `seal:"id"`
+	Title   string `seal:"title"`
+	Version int    `seal:"version,version"`
+}
+
+func (articleModel) TableName() string {
+	return "article"
+}
+
+func TestVersion(t *testing.T) {
+	ctx := context.Background()
+
+	dbfile, err := dbInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open("sqlite3", dbfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.ExecContext(ctx, `CREATE TABLE article (
+		id      INTEGER PRIMARY KEY AUTOINCREMENT,
+		title   VARCHAR (64) NOT NULL,
+		version INTEGER NOT NULL DEFAULT (1)
+	)`).RowsAffected()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := articleModel{Title: randString(8), Version: 1}
+	assert.Nil(t, db.Model(&a).Create(ctx))
+
+	// the update with the current version increments it
+	var cnt int64
+	err = db.Update("article").Value(map[string]interface{}{"title": randString(8)}).
+		Where(Eq("id", a.ID)).Version("version", 1).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+
+	// the stale version is rejected
+	err = db.Update("article").Value(map[string]interface{}{"title": randString(8)}).
+		Where(Eq("id", a.ID)).Version("version", 1).Exec(ctx, &cnt)
+	assert.True(t, errors.Is(err, ErrStaleObject))
+
+	// the explicit version overrides the version of the struct
+	err = db.Update("article").Where(Eq("id", a.ID)).Version("version", 2).
+		Value(articleModel{ID: a.ID, Title: randString(8), Version: 1}).Exec(ctx, &cnt)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+	err = db.Update("article").Version("version", 2).Where(Eq("id", a.ID)).
+		Value(articleModel{ID: a.ID, Title: randString(8), Version: 3}).Exec(ctx, &cnt)
+	assert.True(t, errors.Is(err, ErrStaleObject))
+
+	// model
+	err = db.Model(&a).Save(ctx)
+	assert.True(t, errors.Is(err, ErrStaleObject))
+	assert.Nil(t, db.Model(&a).Find(ctx, a.ID))
+	assert.Equal(t, 3, a.Version)
+	a.Title = randString(8)
+	assert.Nil(t, db.Model(&a).Save(ctx))
+	assert.Equal(t, 4, a.Version)
+	var found articleModel
+	assert.Nil(t, db.Model(&found).Find(ctx, a.ID))
+	assert.Equal(t, a, found)
+}